@@ -0,0 +1,327 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+var now = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func testKeys(t *testing.T) (hs256, hs512, es256 Key) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	return Key{ID: "hs256", Algorithm: HS256, Secret: []byte("secret-256")},
+		Key{ID: "hs512", Algorithm: HS512, Secret: []byte("secret-512")},
+		Key{ID: "es256", Algorithm: ES256, PrivateKey: priv}
+}
+
+func mustSign(t *testing.T, key Key, claims Claims) string {
+	t.Helper()
+	token, err := Sign(key, claims)
+	if err != nil {
+		t.Fatalf("Sign() unexpected error: %v", err)
+	}
+	return token
+}
+
+func validClaims() Claims {
+	return Claims{
+		Issuer:    "betmates",
+		Subject:   "user-42",
+		Audience:  []string{"wallet"},
+		ExpiresAt: now.Add(time.Hour),
+		NotBefore: now.Add(-time.Minute),
+		IssuedAt:  now.Add(-time.Minute),
+		Extra:     map[string]any{"roles": []any{"player"}},
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	hs256, hs512, es256 := testKeys(t)
+	// Verification of ES256 only needs the public key.
+	verifyES := Key{ID: es256.ID, Algorithm: ES256, PublicKey: &es256.PrivateKey.PublicKey}
+	v := NewVerifier(StaticKeySet{"hs256": hs256, "hs512": hs512, "es256": verifyES}, VerifierOptions{
+		Issuer:   "betmates",
+		Audience: "wallet",
+		Leeway:   30 * time.Second,
+		Clock:    clock.NewFake(now),
+	})
+
+	with := func(edit func(*Claims)) Claims {
+		c := validClaims()
+		edit(&c)
+		return c
+	}
+	otherKey := Key{ID: "hs256", Algorithm: HS256, Secret: []byte("wrong")}
+	confused := Key{ID: "es256", Algorithm: HS256, Secret: []byte("public key bytes")}
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode errors.ErrorCode
+	}{
+		{"HS256", mustSign(t, hs256, validClaims()), ""},
+		{"HS512", mustSign(t, hs512, validClaims()), ""},
+		{"ES256", mustSign(t, es256, validClaims()), ""},
+		{"Expired within leeway", mustSign(t, hs256, with(func(c *Claims) { c.ExpiresAt = now.Add(-10 * time.Second) })), ""},
+		{"Expired", mustSign(t, hs256, with(func(c *Claims) { c.ExpiresAt = now.Add(-time.Minute) })), errors.ErrExpiredToken},
+		{"Not yet valid within leeway", mustSign(t, hs256, with(func(c *Claims) { c.NotBefore = now.Add(10 * time.Second) })), ""},
+		{"Not yet valid", mustSign(t, hs256, with(func(c *Claims) { c.NotBefore = now.Add(time.Minute) })), errors.ErrTokenNotYetValid},
+		{"Wrong issuer", mustSign(t, hs256, with(func(c *Claims) { c.Issuer = "evil" })), errors.ErrInvalidIssuer},
+		{"Wrong audience", mustSign(t, hs256, with(func(c *Claims) { c.Audience = []string{"admin"} })), errors.ErrInvalidAudience},
+		{"One of several audiences", mustSign(t, hs256, with(func(c *Claims) { c.Audience = []string{"admin", "wallet"} })), ""},
+		{"Bad signature", mustSign(t, otherKey, validClaims()), errors.ErrInvalidToken},
+		{"Unknown kid", mustSign(t, Key{ID: "old", Algorithm: HS256, Secret: []byte("x")}, validClaims()), errors.ErrInvalidToken},
+		{"Algorithm confusion", mustSign(t, confused, validClaims()), errors.ErrInvalidToken},
+		{"Malformed", "not.a.jwt", errors.ErrInvalidToken},
+		{"Two segments", "a.b", errors.ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Verify(tt.token)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("Verify() unexpected error: %v", err)
+				}
+				if claims.Subject != "user-42" {
+					t.Errorf("Subject = %q, want user-42", claims.Subject)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Verify() expected %s, got nil", tt.wantCode)
+			}
+			if err.Code() != tt.wantCode {
+				t.Errorf("Code() = %v, want %v", err.Code(), tt.wantCode)
+			}
+			if err.Type() != errors.AuthenticationError {
+				t.Errorf("Type() = %v, want %v", err.Type(), errors.AuthenticationError)
+			}
+		})
+	}
+}
+
+func TestVerifier_NoneAlgorithm(t *testing.T) {
+	hs256, _, _ := testKeys(t)
+	v := NewVerifier(StaticKeySet{"hs256": hs256}, VerifierOptions{Clock: clock.NewFake(now)})
+	signed := mustSign(t, hs256, validClaims())
+	parts := strings.Split(signed, ".")
+	none := encode([]byte(`{"alg":"none","kid":"hs256"}`)) + "." + parts[1] + "."
+
+	if _, err := v.Verify(none); err == nil || err.Code() != errors.ErrInvalidToken {
+		t.Errorf("Verify() error = %v, want %s", err, errors.ErrInvalidToken)
+	}
+}
+
+func TestSign_KeyFault(t *testing.T) {
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	for name, key := range map[string]Key{
+		"No private key": {ID: "es256", Algorithm: ES256},
+		"P-384 key":      {ID: "es256", Algorithm: ES256, PrivateKey: p384},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Sign(key, validClaims())
+			if err == nil || err.Code() != errors.ErrInternal || err.Type() != errors.InternalError {
+				t.Errorf("Sign() error = %v, want %s internal error", err, errors.ErrInternal)
+			}
+		})
+	}
+}
+
+func TestVerifier_WrongCurve(t *testing.T) {
+	_, _, es256 := testKeys(t)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	token := mustSign(t, es256, validClaims())
+	v := NewVerifier(StaticKeySet{"es256": {ID: "es256", Algorithm: ES256, PublicKey: &p384.PublicKey}},
+		VerifierOptions{Clock: clock.NewFake(now)})
+	if _, err := v.Verify(token); err == nil || err.Code() != errors.ErrInvalidToken {
+		t.Errorf("Verify() error = %v, want %s", err, errors.ErrInvalidToken)
+	}
+}
+
+func TestVerifier_RequireExpiry(t *testing.T) {
+	hs256, _, _ := testKeys(t)
+	claims := validClaims()
+	claims.ExpiresAt = time.Time{}
+	token := mustSign(t, hs256, claims)
+
+	lenient := NewVerifier(StaticKeySet{"hs256": hs256}, VerifierOptions{Clock: clock.NewFake(now)})
+	if _, err := lenient.Verify(token); err != nil {
+		t.Errorf("Verify() unexpected error without RequireExpiry: %v", err)
+	}
+	strict := NewVerifier(StaticKeySet{"hs256": hs256}, VerifierOptions{RequireExpiry: true, Clock: clock.NewFake(now)})
+	if _, err := strict.Verify(token); err == nil || err.Code() != errors.ErrInvalidToken {
+		t.Errorf("Verify() error = %v, want %s", err, errors.ErrInvalidToken)
+	}
+}
+
+func TestClaimsFromMap_NumericDates(t *testing.T) {
+	tests := []struct {
+		name string
+		exp  string
+		want time.Time
+	}{
+		{"Integer", "1718452800", time.Unix(1718452800, 0).UTC()},
+		{"Fractional", "1718452800.25", time.Unix(1718452800, 250_000_000).UTC()},
+		{"Far future", "10000000000", time.Unix(10_000_000_000, 0).UTC()},
+		{"Far future fractional", "10000000000.5", time.Unix(10_000_000_000, 500_000_000).UTC()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, reason := claimsFromMap(map[string]any{"exp": json.Number(tt.exp)})
+			if reason != "" {
+				t.Fatalf("claimsFromMap() reason = %q", reason)
+			}
+			if !claims.ExpiresAt.Equal(tt.want) {
+				t.Errorf("ExpiresAt = %v, want %v", claims.ExpiresAt, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotatingKeySet(t *testing.T) {
+	oldKey := Key{ID: "2024-01", Algorithm: HS256, Secret: []byte("old")}
+	newKey := Key{ID: "2024-06", Algorithm: HS256, Secret: []byte("new")}
+	keys := NewRotatingKeySet(oldKey)
+	v := NewVerifier(keys, VerifierOptions{Clock: clock.NewFake(now)})
+
+	oldToken := mustSign(t, oldKey, validClaims())
+	keys.Add(newKey)
+	newToken := mustSign(t, newKey, validClaims())
+	for _, token := range []string{oldToken, newToken} {
+		if _, err := v.Verify(token); err != nil {
+			t.Errorf("Verify() during rotation unexpected error: %v", err)
+		}
+	}
+
+	keys.Remove(oldKey.ID)
+	if _, err := v.Verify(oldToken); err == nil {
+		t.Error("Expected token of a retired key to be rejected")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	hs256, _, _ := testKeys(t)
+	v := NewVerifier(StaticKeySet{"hs256": hs256}, VerifierOptions{Issuer: "betmates", Clock: clock.NewFake(now)})
+	h := Middleware(v, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := ClaimsFromContext(r.Context())
+		if !ok {
+			t.Error("Expected claims in context")
+		}
+		_, _ = w.Write([]byte(claims.Subject))
+	}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantBody      string
+	}{
+		{"Valid token", "Bearer " + mustSign(t, hs256, validClaims()), http.StatusOK, "user-42"},
+		{"Lower-case scheme", "bearer " + mustSign(t, hs256, validClaims()), http.StatusOK, "user-42"},
+		{"Missing header", "", http.StatusUnauthorized, string(errors.ErrInvalidToken)},
+		{"Basic scheme", "Basic dXNlcjpwYXNz", http.StatusUnauthorized, string(errors.ErrInvalidToken)},
+		{"Expired token", "Bearer " + mustSign(t, hs256, Claims{Subject: "user-42", ExpiresAt: now.Add(-time.Hour)}), http.StatusUnauthorized, string(errors.ErrExpiredToken)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+			if tt.wantStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected WWW-Authenticate challenge")
+			}
+		})
+	}
+}
+
+// TestCatalogExamples keeps the catalog examples, which feed the OpenAPI and
+// error docs, in step with what the verifier returns.
+func TestCatalogExamples(t *testing.T) {
+	hs256, _, _ := testKeys(t)
+	v := NewVerifier(StaticKeySet{"hs256": hs256}, VerifierOptions{
+		Issuer:   "betmates",
+		Audience: "wallet",
+		Clock:    clock.NewFake(now),
+	})
+	with := func(edit func(*Claims)) Claims {
+		c := validClaims()
+		edit(&c)
+		return c
+	}
+	otherKey := Key{ID: "hs256", Algorithm: HS256, Secret: []byte("wrong")}
+
+	tokens := map[string]string{
+		"Bad signature":  mustSign(t, otherKey, validClaims()),
+		"Expired":        mustSign(t, hs256, with(func(c *Claims) { c.ExpiresAt = now.Add(-time.Hour) })),
+		"Not yet valid":  mustSign(t, hs256, with(func(c *Claims) { c.NotBefore = now.Add(time.Hour) })),
+		"Wrong issuer":   mustSign(t, hs256, with(func(c *Claims) { c.Issuer = "evil" })),
+		"Wrong audience": mustSign(t, hs256, with(func(c *Claims) { c.Audience = []string{"admin"} })),
+	}
+	for name, token := range tokens {
+		t.Run(name, func(t *testing.T) {
+			_, err := v.Verify(token)
+			if err == nil {
+				t.Fatal("Verify() expected an error, got nil")
+			}
+			matchesCatalog(t, err)
+		})
+	}
+}
+
+func matchesCatalog(t *testing.T, err errors.LayerError) {
+	t.Helper()
+	def, ok := errors.Lookup(err.Code())
+	if !ok {
+		t.Fatalf("%s is not in the catalog", err.Code())
+	}
+	want := def.Example()
+	if err.Error() != want.Error() || err.Type() != want.Type() || err.Layer() != want.Layer() {
+		t.Errorf("%s = %q (%s, %s), catalog example = %q (%s, %s)", err.Code(),
+			err.Error(), err.Type(), err.Layer(), want.Error(), want.Type(), want.Layer())
+	}
+	if got, exp := detailKeys(err), detailKeys(want); got != exp {
+		t.Errorf("%s details = [%s], catalog example = [%s]", err.Code(), got, exp)
+	}
+}
+
+func detailKeys(err errors.LayerError) string {
+	keys := make([]string, 0, len(err.Details()))
+	for k := range err.Details() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, " ")
+}
@@ -0,0 +1,272 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// Claims holds the registered JWT claims. Other claims, such as roles, are
+// kept in Extra.
+type Claims struct {
+	Issuer    string
+	Subject   string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time
+	IssuedAt  time.Time
+	ID        string
+	Extra     map[string]any
+}
+
+type header struct {
+	Algorithm Algorithm `json:"alg"`
+	KeyID     string    `json:"kid,omitempty"`
+	Type      string    `json:"typ,omitempty"`
+}
+
+// Sign encodes claims as a compact JWT signed with key.
+func Sign(key Key, claims Claims) (string, errors.LayerError) {
+	h, err := json.Marshal(header{Algorithm: key.Algorithm, KeyID: key.ID, Type: "JWT"})
+	if err != nil {
+		return "", signingError(fmt.Sprintf("Could not encode token header: %v", err))
+	}
+	payload, err := json.Marshal(claims.toMap())
+	if err != nil {
+		return "", signingError(fmt.Sprintf("Could not encode token claims: %v", err))
+	}
+	input := encode(h) + "." + encode(payload)
+	sig, ok := key.sign([]byte(input))
+	if !ok {
+		return "", signingError(fmt.Sprintf("Key '%s' cannot sign %s tokens", key.ID, key.Algorithm))
+	}
+	return input + "." + encode(sig), nil
+}
+
+// VerifierOptions configures the checks of a Verifier. Empty Issuer and
+// Audience skip the corresponding check.
+type VerifierOptions struct {
+	Issuer   string
+	Audience string
+	// Leeway tolerates clock skew between issuer and verifier on exp and nbf.
+	Leeway time.Duration
+	// RequireExpiry rejects tokens without an exp claim, which would
+	// otherwise be valid forever, with ErrInvalidToken.
+	RequireExpiry bool
+	// Clock defaults to clock.System().
+	Clock clock.Clock
+}
+
+type Verifier struct {
+	keys KeySet
+	opts VerifierOptions
+}
+
+func NewVerifier(keys KeySet, opts VerifierOptions) *Verifier {
+	if opts.Clock == nil {
+		opts.Clock = clock.System()
+	}
+	return &Verifier{keys: keys, opts: opts}
+}
+
+// Verify checks the signature and claims of token. Every failure is an
+// AuthenticationError: ErrInvalidToken for malformed tokens, unknown keys and
+// bad signatures, ErrExpiredToken, ErrTokenNotYetValid, ErrInvalidIssuer and
+// ErrInvalidAudience for the claim checks.
+func (v *Verifier) Verify(token string) (Claims, errors.LayerError) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, invalidToken("token must have three segments")
+	}
+	var h header
+	if err := decodeJSON(parts[0], &h); err != nil {
+		return Claims{}, invalidToken("malformed header")
+	}
+	key, ok := v.keys.Key(h.KeyID)
+	if !ok {
+		return Claims{}, invalidToken(fmt.Sprintf("unknown key id '%s'", h.KeyID))
+	}
+	// The algorithm is taken from the key, never from the token, so a token
+	// cannot downgrade to "none" or swap ES256 for HMAC.
+	if h.Algorithm != key.Algorithm {
+		return Claims{}, invalidToken(fmt.Sprintf("algorithm '%s' does not match key '%s'", h.Algorithm, key.ID))
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !key.verify([]byte(parts[0]+"."+parts[1]), sig) {
+		return Claims{}, invalidToken("signature verification failed")
+	}
+
+	var raw map[string]any
+	if err := decodeJSON(parts[1], &raw); err != nil {
+		return Claims{}, invalidToken("malformed claims")
+	}
+	claims, reason := claimsFromMap(raw)
+	if reason != "" {
+		return Claims{}, invalidToken(reason)
+	}
+	return claims, v.checkClaims(claims)
+}
+
+func (v *Verifier) checkClaims(c Claims) errors.LayerError {
+	now := v.opts.Clock.Now()
+	if v.opts.RequireExpiry && c.ExpiresAt.IsZero() {
+		return invalidToken("claim 'exp' is required")
+	}
+	if !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt.Add(v.opts.Leeway)) {
+		return errors.NewAuthenticationError(errors.ErrExpiredToken, "Token has expired",
+			map[string]any{"expired_at": c.ExpiresAt})
+	}
+	if !c.NotBefore.IsZero() && now.Before(c.NotBefore.Add(-v.opts.Leeway)) {
+		return errors.NewAuthenticationError(errors.ErrTokenNotYetValid, "Token is not valid yet",
+			map[string]any{"not_before": c.NotBefore})
+	}
+	if v.opts.Issuer != "" && c.Issuer != v.opts.Issuer {
+		return errors.NewAuthenticationError(errors.ErrInvalidIssuer,
+			fmt.Sprintf("Token issuer '%s' is not trusted", c.Issuer),
+			map[string]any{"issuer": c.Issuer, "expected_issuer": v.opts.Issuer})
+	}
+	if v.opts.Audience != "" && !containsString(c.Audience, v.opts.Audience) {
+		return errors.NewAuthenticationError(errors.ErrInvalidAudience,
+			fmt.Sprintf("Token is not intended for '%s'", v.opts.Audience),
+			map[string]any{"audience": c.Audience, "expected_audience": v.opts.Audience})
+	}
+	return nil
+}
+
+var registered = map[string]bool{"iss": true, "sub": true, "aud": true, "exp": true, "nbf": true, "iat": true, "jti": true}
+
+func (c Claims) toMap() map[string]any {
+	m := make(map[string]any, len(c.Extra)+len(registered))
+	for k, v := range c.Extra {
+		m[k] = v
+	}
+	setString(m, "iss", c.Issuer)
+	setString(m, "sub", c.Subject)
+	setString(m, "jti", c.ID)
+	switch len(c.Audience) {
+	case 0:
+	case 1:
+		m["aud"] = c.Audience[0]
+	default:
+		m["aud"] = c.Audience
+	}
+	setTime(m, "exp", c.ExpiresAt)
+	setTime(m, "nbf", c.NotBefore)
+	setTime(m, "iat", c.IssuedAt)
+	return m
+}
+
+// claimsFromMap reads the registered claims, returning a reason when one has
+// the wrong type.
+func claimsFromMap(m map[string]any) (Claims, string) {
+	var c Claims
+	var ok bool
+	for name, dst := range map[string]*string{"iss": &c.Issuer, "sub": &c.Subject, "jti": &c.ID} {
+		if v, present := m[name]; present {
+			if *dst, ok = v.(string); !ok {
+				return Claims{}, fmt.Sprintf("claim '%s' must be a string", name)
+			}
+		}
+	}
+	for name, dst := range map[string]*time.Time{"exp": &c.ExpiresAt, "nbf": &c.NotBefore, "iat": &c.IssuedAt} {
+		if v, present := m[name]; present {
+			n, isNumber := v.(json.Number)
+			t, valid := numericDate(n)
+			if !isNumber || !valid {
+				return Claims{}, fmt.Sprintf("claim '%s' must be a numeric date", name)
+			}
+			*dst = t
+		}
+	}
+	switch aud := m["aud"].(type) {
+	case nil:
+	case string:
+		c.Audience = []string{aud}
+	case []any:
+		for _, a := range aud {
+			s, isString := a.(string)
+			if !isString {
+				return Claims{}, "claim 'aud' must be a string or an array of strings"
+			}
+			c.Audience = append(c.Audience, s)
+		}
+	default:
+		return Claims{}, "claim 'aud' must be a string or an array of strings"
+	}
+	for k, v := range m {
+		if !registered[k] {
+			if c.Extra == nil {
+				c.Extra = make(map[string]any)
+			}
+			c.Extra[k] = v
+		}
+	}
+	return c, ""
+}
+
+// numericDate converts seconds since the epoch, possibly fractional, keeping
+// whole seconds and nanoseconds apart so that far-future dates do not
+// overflow a time.Duration.
+func numericDate(n json.Number) (time.Time, bool) {
+	if sec, err := n.Int64(); err == nil {
+		return time.Unix(sec, 0).UTC(), true
+	}
+	f, err := n.Float64()
+	if err != nil || math.Abs(f) >= math.MaxInt64 {
+		return time.Time{}, false
+	}
+	sec := math.Floor(f)
+	return time.Unix(int64(sec), int64(math.Round((f-sec)*1e9))).UTC(), true
+}
+
+func invalidToken(reason string) errors.LayerError {
+	return errors.NewAuthenticationError(errors.ErrInvalidToken, "Token is invalid", map[string]any{"reason": reason})
+}
+
+// signingError reports a misconfigured signing key or unencodable claims,
+// which are server faults rather than client errors.
+func signingError(message string) errors.LayerError {
+	return errors.NewApplicationError(errors.ErrInternal, errors.InternalError, message)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeJSON(segment string, dst any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(dst)
+}
+
+func setString(m map[string]any, name, value string) {
+	if value != "" {
+		m[name] = value
+	}
+}
+
+func setTime(m map[string]any, name string, t time.Time) {
+	if !t.IsZero() {
+		m[name] = t.Unix()
+	}
+}
+
+func containsString(values []string, v string) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"math/big"
+	"sync"
+)
+
+type Algorithm string
+
+const (
+	HS256 Algorithm = "HS256"
+	HS512 Algorithm = "HS512"
+	ES256 Algorithm = "ES256"
+)
+
+// Key is a signing or verification key identified by the "kid" header. HMAC
+// keys set Secret; ES256 keys set PublicKey, and PrivateKey when used to sign,
+// both on the P-256 curve.
+type Key struct {
+	ID         string
+	Algorithm  Algorithm
+	Secret     []byte
+	PublicKey  *ecdsa.PublicKey
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// KeySet resolves verification keys by kid.
+type KeySet interface {
+	Key(kid string) (Key, bool)
+}
+
+// StaticKeySet is a fixed KeySet, e.g. loaded from configuration.
+type StaticKeySet map[string]Key
+
+func (s StaticKeySet) Key(kid string) (Key, bool) {
+	k, ok := s[kid]
+	return k, ok
+}
+
+// RotatingKeySet is a KeySet whose keys can be added and retired while it is
+// in use. During a rotation the new key is added first, tokens are signed
+// with it, and the old key is removed once its tokens have expired.
+type RotatingKeySet struct {
+	mu   sync.RWMutex
+	keys map[string]Key
+}
+
+func NewRotatingKeySet(keys ...Key) *RotatingKeySet {
+	s := &RotatingKeySet{keys: make(map[string]Key, len(keys))}
+	for _, k := range keys {
+		s.keys[k.ID] = k
+	}
+	return s
+}
+
+func (s *RotatingKeySet) Key(kid string) (Key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+func (s *RotatingKeySet) Add(k Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[k.ID] = k
+}
+
+func (s *RotatingKeySet) Remove(kid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, kid)
+}
+
+func (k Key) sign(input []byte) ([]byte, bool) {
+	switch k.Algorithm {
+	case HS256, HS512:
+		if len(k.Secret) == 0 {
+			return nil, false
+		}
+		mac := hmac.New(k.hash(), k.Secret)
+		mac.Write(input)
+		return mac.Sum(nil), true
+	case ES256:
+		if k.PrivateKey == nil || k.PrivateKey.Curve != elliptic.P256() {
+			return nil, false
+		}
+		digest := sha256.Sum256(input)
+		r, s, err := ecdsa.Sign(rand.Reader, k.PrivateKey, digest[:])
+		if err != nil {
+			return nil, false
+		}
+		// JWS encodes ES256 signatures as fixed-size R || S.
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig, true
+	}
+	return nil, false
+}
+
+func (k Key) verify(input, sig []byte) bool {
+	switch k.Algorithm {
+	case HS256, HS512:
+		expected, ok := k.sign(input)
+		return ok && hmac.Equal(expected, sig)
+	case ES256:
+		pub := k.PublicKey
+		if pub == nil && k.PrivateKey != nil {
+			pub = &k.PrivateKey.PublicKey
+		}
+		if pub == nil || pub.Curve != elliptic.P256() || len(sig) != 64 {
+			return false
+		}
+		digest := sha256.Sum256(input)
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(pub, digest[:], r, s)
+	}
+	return false
+}
+
+func (k Key) hash() func() hash.Hash {
+	if k.Algorithm == HS512 {
+		return sha512.New
+	}
+	return sha256.New
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/protocols"
+)
+
+type claimsKey struct{}
+
+// WithClaims returns a copy of ctx carrying claims.
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims stored by Middleware.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(Claims)
+	return claims, ok
+}
+
+// Middleware verifies the bearer token of every request and stores its claims
+// in the request context. Failures are written with handler, which defaults
+// to protocols.NewDefaultHTTPErrorHandler(), together with a
+// WWW-Authenticate challenge.
+func Middleware(v *Verifier, handler protocols.HTTPErrorHandler) func(http.Handler) http.Handler {
+	if handler == nil {
+		handler = protocols.NewDefaultHTTPErrorHandler()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer`)
+				protocols.WriteHTTPError(w, handler, errors.NewAuthenticationError(errors.ErrInvalidToken,
+					"Bearer token is required", map[string]any{"reason": "missing bearer token"}))
+				return
+			}
+			claims, err := v.Verify(token)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				protocols.WriteHTTPError(w, handler, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
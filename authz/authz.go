@@ -0,0 +1,292 @@
+package authz
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// Subject is the caller being authorized.
+type Subject struct {
+	ID         string
+	Roles      []string
+	Attributes map[string]any
+}
+
+// Request asks whether Subject may use Permission, a "resource:action" string,
+// on a resource described by its attributes.
+type Request struct {
+	Subject    Subject
+	Permission string
+	Resource   map[string]any
+}
+
+// Decision explains the outcome of a request. Rule is the id of the rule that
+// decided it, or "role:<name>" for a role grant, and is empty when nothing
+// granted the permission.
+type Decision struct {
+	Allowed    bool
+	Permission string
+	Rule       string
+	Reason     string
+}
+
+type Engine struct {
+	policy      Policy
+	permissions map[string][]string
+}
+
+// New validates the policy and resolves role inheritance.
+func New(policy Policy) (*Engine, errors.LayerError) {
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+	e := &Engine{policy: policy, permissions: make(map[string][]string, len(policy.Roles))}
+	for name := range policy.Roles {
+		perms, err := e.resolve(name, nil)
+		if err != nil {
+			return nil, err
+		}
+		e.permissions[name] = perms
+	}
+	return e, nil
+}
+
+func (e *Engine) resolve(role string, path []string) ([]string, errors.LayerError) {
+	for _, seen := range path {
+		if seen == role {
+			return nil, invalidPolicy(fmt.Sprintf("role inheritance cycle: %s", strings.Join(append(path, role), " -> ")),
+				map[string]any{"role": role})
+		}
+	}
+	def := e.policy.Roles[role]
+	perms := append([]string(nil), def.Permissions...)
+	for _, parent := range def.Inherits {
+		inherited, err := e.resolve(parent, append(path, role))
+		if err != nil {
+			return nil, err
+		}
+		perms = append(perms, inherited...)
+	}
+	return perms, nil
+}
+
+// Authorize evaluates the request. Matching deny rules are checked first, then
+// role grants, then allow rules; anything not granted is denied.
+func (e *Engine) Authorize(req Request) Decision {
+	d := Decision{Permission: req.Permission}
+	for _, rule := range e.policy.Rules {
+		if rule.Effect == Deny && e.applies(rule, req) {
+			d.Rule = rule.ID
+			d.Reason = fmt.Sprintf("denied by rule '%s'", rule.ID)
+			return d
+		}
+	}
+	for _, role := range req.Subject.Roles {
+		for _, pattern := range e.permissions[role] {
+			if Match(pattern, req.Permission) {
+				d.Allowed = true
+				d.Rule = "role:" + role
+				d.Reason = fmt.Sprintf("granted by role '%s'", role)
+				return d
+			}
+		}
+	}
+	for _, rule := range e.policy.Rules {
+		if rule.Effect == Allow && e.applies(rule, req) {
+			d.Allowed = true
+			d.Rule = rule.ID
+			d.Reason = fmt.Sprintf("granted by rule '%s'", rule.ID)
+			return d
+		}
+	}
+	d.Reason = "no role or rule grants the permission"
+	return d
+}
+
+// Check returns nil when the request is allowed, and otherwise an
+// AuthorizationError: ErrAccessDenied when a deny rule matched and
+// ErrInsufficientPermissions when nothing granted the permission. Details
+// carry the required permission, the subject and the decision.
+func (e *Engine) Check(req Request) errors.LayerError {
+	d := e.Authorize(req)
+	if d.Allowed {
+		return nil
+	}
+	details := map[string]any{
+		"permission": req.Permission,
+		"subject":    req.Subject.ID,
+		"reason":     d.Reason,
+	}
+	if d.Rule != "" {
+		details["rule"] = d.Rule
+		return errors.NewAuthorizationError(errors.ErrAccessDenied,
+			fmt.Sprintf("Access to '%s' is denied", req.Permission), details)
+	}
+	return errors.NewAuthorizationError(errors.ErrInsufficientPermissions,
+		fmt.Sprintf("Permission '%s' is required", req.Permission), details)
+}
+
+func (e *Engine) applies(rule Rule, req Request) bool {
+	if len(rule.Roles) > 0 && !hasAnyRole(req.Subject.Roles, rule.Roles) {
+		return false
+	}
+	matched := false
+	for _, pattern := range rule.Permissions {
+		if Match(pattern, req.Permission) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false
+	}
+	for _, c := range rule.Conditions {
+		if !c.holds(req, rule.Effect) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match reports whether permission matches pattern. Segments are separated
+// by ":" and "*" matches any one segment; a trailing "*" matches one or more
+// remaining segments, so "*" alone matches everything and "bets:*" matches
+// "bets:read" but not the bare "bets".
+func Match(pattern, permission string) bool {
+	ps := strings.Split(pattern, ":")
+	xs := strings.Split(permission, ":")
+	for i, p := range ps {
+		if p == "*" && i == len(ps)-1 {
+			return i < len(xs)
+		}
+		if i >= len(xs) || (p != "*" && p != xs[i]) {
+			return false
+		}
+	}
+	return len(ps) == len(xs)
+}
+
+// holds evaluates the condition for a rule with the given effect. A missing
+// Ref attribute, or an "in" list that is not a slice or array, leaves the
+// comparison undecided, so the condition fails closed: it holds for deny
+// rules and not for allow rules. Otherwise a tenant isolation rule comparing
+// with "subject.tenant_id" would let a token without the attribute through.
+func (c Condition) holds(req Request, effect Effect) bool {
+	actual, present := attribute(req, c.Attribute)
+	if c.Operator == Exists {
+		return present
+	}
+	expected := c.Value
+	if c.Ref != "" {
+		var ok bool
+		if expected, ok = attribute(req, c.Ref); !ok {
+			return effect == Deny
+		}
+	}
+	switch c.Operator {
+	case Equals:
+		return present && sameValue(actual, expected)
+	case NotEquals:
+		return !present || !sameValue(actual, expected)
+	case In:
+		list, ok := listOf(expected)
+		if !ok {
+			return effect == Deny
+		}
+		if !present {
+			return false
+		}
+		for _, v := range list {
+			if sameValue(actual, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// listOf reads any slice or array, such as the []any of a JSON policy or the
+// []string of a subject attribute, as a list of values.
+func listOf(v any) ([]any, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	list := make([]any, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, true
+}
+
+// attribute resolves "subject.id", "subject.<attribute>" and
+// "resource.<attribute>".
+func attribute(req Request, name string) (any, bool) {
+	scope, key, _ := strings.Cut(name, ".")
+	switch scope {
+	case "subject":
+		if key == "id" {
+			return req.Subject.ID, req.Subject.ID != ""
+		}
+		v, ok := req.Subject.Attributes[key]
+		return v, ok
+	case "resource":
+		v, ok := req.Resource[key]
+		return v, ok
+	}
+	return nil, false
+}
+
+// sameValue compares values of the same type. Numbers of any Go numeric type
+// or json.Number compare by value, so that a JSON number in the policy
+// equals an int attribute, but the string "1" does not equal the number 1.
+func sameValue(a, b any) bool {
+	x, aNumber := number(a)
+	y, bNumber := number(b)
+	if aNumber || bNumber {
+		return aNumber && bNumber && x.Cmp(y) == 0
+	}
+	if a == nil || b == nil || !reflect.TypeOf(a).Comparable() || !reflect.TypeOf(b).Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
+// number converts numeric values to an exact rational. NaN and infinities
+// are not numbers here.
+func number(v any) (*big.Rat, bool) {
+	if n, ok := v.(json.Number); ok {
+		return new(big.Rat).SetString(n.String())
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Rat).SetFrac(new(big.Int).SetUint64(rv.Uint()), big.NewInt(1)), true
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, false
+		}
+		return new(big.Rat).SetFloat64(f), true
+	}
+	return nil, false
+}
+
+func hasAnyRole(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,307 @@
+package authz
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/auth"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+const testPolicy = `{
+  "roles": {
+    "player": {"permissions": ["bets:read", "bets:create"]},
+    "trader": {"inherits": ["player"], "permissions": ["markets:*"]},
+    "admin":  {"permissions": ["*"]}
+  },
+  "rules": [
+    {"id": "cancel-own-bet", "effect": "allow", "roles": ["player"], "permissions": ["bets:cancel"],
+     "conditions": [{"attribute": "resource.owner_id", "operator": "eq", "ref": "subject.id"}]},
+    {"id": "tenant-isolation", "effect": "deny", "permissions": ["*"],
+     "conditions": [{"attribute": "resource.tenant_id", "operator": "exists"},
+                    {"attribute": "resource.tenant_id", "operator": "ne", "ref": "subject.tenant_id"}]},
+    {"id": "restricted-markets", "effect": "deny", "permissions": ["bets:create"],
+     "conditions": [{"attribute": "resource.market", "operator": "in", "value": ["politics", "esports-u18"]}]}
+  ]
+}`
+
+func newTestEngine(t *testing.T) *Engine {
+	t.Helper()
+	policy, err := ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("ParsePolicy() unexpected error: %v", err)
+	}
+	e, err := New(policy)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	return e
+}
+
+func TestEngine_Check(t *testing.T) {
+	e := newTestEngine(t)
+	player := Subject{ID: "u1", Roles: []string{"player"}, Attributes: map[string]any{"tenant_id": "mx"}}
+	trader := Subject{ID: "u2", Roles: []string{"trader"}, Attributes: map[string]any{"tenant_id": "mx"}}
+	admin := Subject{ID: "u3", Roles: []string{"admin"}, Attributes: map[string]any{"tenant_id": "mx"}}
+
+	tests := []struct {
+		name       string
+		subject    Subject
+		permission string
+		resource   map[string]any
+		wantCode   errors.ErrorCode
+		wantRule   string
+	}{
+		{"Role grant", player, "bets:read", nil, "", "role:player"},
+		{"Missing permission", player, "markets:suspend", nil, errors.ErrInsufficientPermissions, ""},
+		{"Inherited grant", trader, "bets:create", nil, "", "role:trader"},
+		{"Segment wildcard", trader, "markets:suspend", nil, "", "role:trader"},
+		{"Global wildcard", admin, "ledger:adjust:manual", nil, "", "role:admin"},
+		{"Owner condition holds", player, "bets:cancel", map[string]any{"owner_id": "u1"}, "", "cancel-own-bet"},
+		{"Owner condition fails", player, "bets:cancel", map[string]any{"owner_id": "u9"}, errors.ErrInsufficientPermissions, ""},
+		{"Tenant mismatch denies admin", admin, "bets:read", map[string]any{"tenant_id": "co"}, errors.ErrAccessDenied, "tenant-isolation"},
+		{"Tenant match", player, "bets:read", map[string]any{"tenant_id": "mx"}, "", "role:player"},
+		{"In condition denies", player, "bets:create", map[string]any{"market": "politics"}, errors.ErrAccessDenied, "restricted-markets"},
+		{"In condition passes", player, "bets:create", map[string]any{"market": "football"}, "", "role:player"},
+		{"No roles", Subject{ID: "u4"}, "bets:read", nil, errors.ErrInsufficientPermissions, ""},
+		{"Token without tenant is denied", Subject{ID: "u5", Roles: []string{"admin"}}, "bets:read", map[string]any{"tenant_id": "co"}, errors.ErrAccessDenied, "tenant-isolation"},
+		{"Owner condition without subject id", Subject{Roles: []string{"player"}}, "bets:cancel", map[string]any{"owner_id": ""}, errors.ErrInsufficientPermissions, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := Request{Subject: tt.subject, Permission: tt.permission, Resource: tt.resource}
+			d := e.Authorize(req)
+			if d.Rule != tt.wantRule {
+				t.Errorf("Decision.Rule = %q, want %q (%s)", d.Rule, tt.wantRule, d.Reason)
+			}
+			err := e.Check(req)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("Check() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Check() expected %s, got nil", tt.wantCode)
+			}
+			if err.Code() != tt.wantCode || err.Type() != errors.AuthorizationError {
+				t.Errorf("Check() = %s/%s, want %s/%s", err.Code(), err.Type(), tt.wantCode, errors.AuthorizationError)
+			}
+			if err.Details()["permission"] != tt.permission {
+				t.Errorf("Details[permission] = %v, want %s", err.Details()["permission"], tt.permission)
+			}
+		})
+	}
+}
+
+func TestCondition_In(t *testing.T) {
+	blocked := Condition{Attribute: "resource.market", Operator: In, Ref: "subject.blocked_markets"}
+	tests := []struct {
+		name    string
+		blocked any
+		effect  Effect
+		want    bool
+	}{
+		{"Go slice", []string{"politics", "esports"}, Deny, true},
+		{"JSON list", []any{"politics"}, Deny, true},
+		{"Not listed", []string{"esports"}, Deny, false},
+		{"Unreadable list denies", "politics", Deny, true},
+		{"Unreadable list does not allow", "politics", Allow, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := Request{
+				Subject:  Subject{ID: "u1", Attributes: map[string]any{"blocked_markets": tt.blocked}},
+				Resource: map[string]any{"market": "politics"},
+			}
+			if got := blocked.holds(req, tt.effect); got != tt.want {
+				t.Errorf("holds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameValue(t *testing.T) {
+	tests := []struct {
+		a, b any
+		want bool
+	}{
+		{"mx", "mx", true},
+		{"mx", "co", false},
+		{float64(1), 1, true},
+		{json.Number("2"), int64(2), true},
+		{float64(1.5), 1, false},
+		{"1", 1, false},
+		{true, "true", false},
+		{nil, nil, true},
+		{nil, "<nil>", false},
+		{[]any{"a"}, []any{"a"}, true},
+	}
+	for _, tt := range tests {
+		if got := sameValue(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameValue(%#v, %#v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, permission string
+		want                bool
+	}{
+		{"bets:read", "bets:read", true},
+		{"bets:read", "bets:create", false},
+		{"bets:*", "bets:create", true},
+		{"bets:*", "bets:cashout:partial", true},
+		{"*:read", "markets:read", true},
+		{"*:read", "markets:write", false},
+		{"bets:read", "bets:read:all", false},
+		{"*", "anything:at:all", true},
+		{"bets:*", "bets", false},
+		{"*:*", "bets", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.permission); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestNew_InvalidPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+	}{
+		{"Unknown field", `{"roles": {}, "grants": []}`},
+		{"Unknown parent", `{"roles": {"a": {"inherits": ["ghost"]}}}`},
+		{"Cycle", `{"roles": {"a": {"inherits": ["b"]}, "b": {"inherits": ["a"]}}}`},
+		{"Unknown effect", `{"rules": [{"id": "r", "effect": "maybe", "permissions": ["*"]}]}`},
+		{"Unknown operator", `{"rules": [{"id": "r", "effect": "allow", "permissions": ["*"], "conditions": [{"attribute": "subject.id", "operator": "like"}]}]}`},
+		{"Duplicate rule", `{"rules": [{"id": "r", "effect": "allow"}, {"id": "r", "effect": "deny"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ParsePolicy([]byte(tt.policy))
+			if err == nil {
+				_, err = New(policy)
+			}
+			if err == nil || err.Code() != errors.ErrInvalidFormat {
+				t.Errorf("Expected %s, got %v", errors.ErrInvalidFormat, err)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(testPolicy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy() unexpected error: %v", err)
+	}
+	if len(policy.Rules) != 3 {
+		t.Errorf("len(Rules) = %d, want 3", len(policy.Rules))
+	}
+	if _, err := LoadPolicy(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	e := newTestEngine(t)
+	h := Middleware(e, "bets:cancel", MiddlewareOptions{
+		Resource: func(r *http.Request) map[string]any {
+			return map[string]any{"owner_id": r.URL.Query().Get("owner")}
+		},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	player := auth.Claims{Subject: "u1", Extra: map[string]any{"roles": []any{"player"}}}
+	tests := []struct {
+		name       string
+		claims     *auth.Claims
+		owner      string
+		wantStatus int
+		wantBody   string
+	}{
+		{"Owner", &player, "u1", http.StatusOK, ""},
+		{"Not owner", &player, "u2", http.StatusForbidden, string(errors.ErrInsufficientPermissions)},
+		{"Anonymous", nil, "u1", http.StatusUnauthorized, string(errors.ErrInvalidCredentials)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/bets/1/cancel?owner="+tt.owner, nil)
+			if tt.claims != nil {
+				req = req.WithContext(auth.WithClaims(req.Context(), *tt.claims))
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+// TestCatalogExamples keeps the catalog examples, which feed the OpenAPI and
+// error docs, in step with what Check returns.
+func TestCatalogExamples(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`{
+  "roles": {"player": {"permissions": ["bets:read", "wallet:*"]}},
+  "rules": [{"id": "self-excluded", "effect": "deny", "permissions": ["wallet:withdraw"]}]
+}`))
+	if err != nil {
+		t.Fatalf("ParsePolicy() unexpected error: %v", err)
+	}
+	e, err := New(policy)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	player := Subject{ID: "user-42", Roles: []string{"player"}}
+
+	for _, permission := range []string{"bets:settle", "wallet:withdraw"} {
+		t.Run(permission, func(t *testing.T) {
+			err := e.Check(Request{Subject: player, Permission: permission})
+			if err == nil {
+				t.Fatal("Check() expected an error, got nil")
+			}
+			matchesCatalog(t, err)
+		})
+	}
+}
+
+func matchesCatalog(t *testing.T, err errors.LayerError) {
+	t.Helper()
+	def, ok := errors.Lookup(err.Code())
+	if !ok {
+		t.Fatalf("%s is not in the catalog", err.Code())
+	}
+	want := def.Example()
+	if err.Error() != want.Error() || err.Type() != want.Type() || err.Layer() != want.Layer() {
+		t.Errorf("%s = %q (%s, %s), catalog example = %q (%s, %s)", err.Code(),
+			err.Error(), err.Type(), err.Layer(), want.Error(), want.Type(), want.Layer())
+	}
+	if got, exp := detailKeys(err), detailKeys(want); got != exp {
+		t.Errorf("%s details = [%s], catalog example = [%s]", err.Code(), got, exp)
+	}
+}
+
+func detailKeys(err errors.LayerError) string {
+	keys := make([]string, 0, len(err.Details()))
+	for k := range err.Details() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, " ")
+}
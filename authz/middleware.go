@@ -0,0 +1,74 @@
+package authz
+
+import (
+	"net/http"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/auth"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/protocols"
+)
+
+type MiddlewareOptions struct {
+	// Handler renders failures. Defaults to
+	// protocols.NewDefaultHTTPErrorHandler().
+	Handler protocols.HTTPErrorHandler
+	// Subject extracts the caller. Defaults to SubjectFromClaims on the
+	// claims stored by auth.Middleware.
+	Subject func(r *http.Request) (Subject, bool)
+	// Resource describes the target of the request for attribute conditions.
+	Resource func(r *http.Request) map[string]any
+}
+
+// Middleware requires permission on every request. Requests without a
+// subject fail with ErrInvalidCredentials (401); denied requests fail with the
+// AuthorizationError of Engine.Check (403).
+func Middleware(e *Engine, permission string, opts MiddlewareOptions) func(http.Handler) http.Handler {
+	if opts.Handler == nil {
+		opts.Handler = protocols.NewDefaultHTTPErrorHandler()
+	}
+	if opts.Subject == nil {
+		opts.Subject = func(r *http.Request) (Subject, bool) {
+			claims, ok := auth.ClaimsFromContext(r.Context())
+			if !ok {
+				return Subject{}, false
+			}
+			return SubjectFromClaims(claims), true
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject, ok := opts.Subject(r)
+			if !ok {
+				protocols.WriteHTTPError(w, opts.Handler, errors.NewAuthenticationError(errors.ErrInvalidCredentials,
+					"Authentication is required", map[string]any{"permission": permission}))
+				return
+			}
+			req := Request{Subject: subject, Permission: permission}
+			if opts.Resource != nil {
+				req.Resource = opts.Resource(r)
+			}
+			if err := e.Check(req); err != nil {
+				protocols.WriteHTTPError(w, opts.Handler, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SubjectFromClaims uses the token subject as ID, the "roles" claim as roles
+// and every non-registered claim as an attribute.
+func SubjectFromClaims(claims auth.Claims) Subject {
+	s := Subject{ID: claims.Subject, Attributes: claims.Extra}
+	switch roles := claims.Extra["roles"].(type) {
+	case []string:
+		s.Roles = roles
+	case []any:
+		for _, r := range roles {
+			if name, ok := r.(string); ok {
+				s.Roles = append(s.Roles, name)
+			}
+		}
+	}
+	return s
+}
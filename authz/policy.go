@@ -0,0 +1,136 @@
+package authz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+type Operator string
+
+const (
+	Equals    Operator = "eq"
+	NotEquals Operator = "ne"
+	In        Operator = "in"
+	Exists    Operator = "exists"
+)
+
+// Policy is the document loaded from a policy file:
+//
+//	{
+//	  "roles": {
+//	    "player": {"permissions": ["bets:read", "bets:create"]},
+//	    "trader": {"inherits": ["player"], "permissions": ["markets:*"]},
+//	    "admin":  {"permissions": ["*"]}
+//	  },
+//	  "rules": [
+//	    {"id": "cancel-own-bet", "effect": "allow", "roles": ["player"],
+//	     "permissions": ["bets:cancel"],
+//	     "conditions": [{"attribute": "resource.owner_id", "operator": "eq", "ref": "subject.id"}]},
+//	    {"id": "tenant-isolation", "effect": "deny", "permissions": ["*"],
+//	     "conditions": [{"attribute": "resource.tenant_id", "operator": "ne", "ref": "subject.tenant_id"}]}
+//	  ]
+//	}
+type Policy struct {
+	Roles map[string]Role `json:"roles"`
+	Rules []Rule          `json:"rules"`
+}
+
+// Role grants its permissions unconditionally, plus those of the roles it
+// inherits.
+type Role struct {
+	Inherits    []string `json:"inherits,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+// Rule grants or denies permissions when all of its conditions hold. Empty
+// Roles applies the rule to every subject. Deny rules win over any grant.
+type Rule struct {
+	ID          string      `json:"id"`
+	Effect      Effect      `json:"effect"`
+	Roles       []string    `json:"roles,omitempty"`
+	Permissions []string    `json:"permissions"`
+	Conditions  []Condition `json:"conditions,omitempty"`
+}
+
+// Condition compares an attribute, "subject.<name>" or "resource.<name>",
+// with a literal Value or with another attribute named by Ref. A condition
+// whose Ref is missing holds for deny rules and not for allow rules.
+type Condition struct {
+	Attribute string   `json:"attribute"`
+	Operator  Operator `json:"operator"`
+	Value     any      `json:"value,omitempty"`
+	Ref       string   `json:"ref,omitempty"`
+}
+
+// LoadPolicy reads a JSON policy file.
+func LoadPolicy(path string) (Policy, errors.LayerError) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, errors.NewInfrastructureError(errors.ErrRepositoryOperation,
+			fmt.Sprintf("Could not read policy file '%s': %v", path, err),
+			map[string]any{"path": path})
+	}
+	return ParsePolicy(data)
+}
+
+// ParsePolicy decodes a JSON policy, rejecting unknown fields.
+func ParsePolicy(data []byte) (Policy, errors.LayerError) {
+	var p Policy
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return Policy{}, invalidPolicy(fmt.Sprintf("malformed policy: %v", err), nil)
+	}
+	return p, nil
+}
+
+func (p Policy) validate() errors.LayerError {
+	for name, role := range p.Roles {
+		for _, parent := range role.Inherits {
+			if _, ok := p.Roles[parent]; !ok {
+				return invalidPolicy(fmt.Sprintf("role '%s' inherits unknown role '%s'", name, parent),
+					map[string]any{"role": name})
+			}
+		}
+	}
+	ids := make(map[string]bool, len(p.Rules))
+	for i, rule := range p.Rules {
+		if rule.ID == "" {
+			return invalidPolicy(fmt.Sprintf("rule %d has no id", i), map[string]any{"rule_index": i})
+		}
+		if ids[rule.ID] {
+			return invalidPolicy(fmt.Sprintf("rule id '%s' is duplicated", rule.ID), map[string]any{"rule": rule.ID})
+		}
+		ids[rule.ID] = true
+		if rule.Effect != Allow && rule.Effect != Deny {
+			return invalidPolicy(fmt.Sprintf("rule '%s' has unknown effect '%s'", rule.ID, rule.Effect), map[string]any{"rule": rule.ID})
+		}
+		for _, c := range rule.Conditions {
+			switch c.Operator {
+			case Equals, NotEquals, In, Exists:
+			default:
+				return invalidPolicy(fmt.Sprintf("rule '%s' has unknown operator '%s'", rule.ID, c.Operator), map[string]any{"rule": rule.ID})
+			}
+		}
+	}
+	return nil
+}
+
+func invalidPolicy(reason string, details map[string]any) errors.LayerError {
+	if details == nil {
+		details = map[string]any{}
+	}
+	details["reason"] = reason
+	return errors.NewValidationError(errors.ErrInvalidFormat, "Invalid authorization policy: "+reason, details)
+}
@@ -0,0 +1,137 @@
+package betslip
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/odds"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/validation"
+)
+
+type Selection struct {
+	ID      string
+	EventID string
+	Market  string
+	Odds    odds.Odds
+}
+
+// Slip is a bet placement request. A slip with several selections is an
+// accumulator (parlay) whose odds are the product of the selection odds.
+type Slip struct {
+	Stake        money.Amount
+	Jurisdiction string
+	Selections   []Selection
+}
+
+// CombinedOdds returns the exact product of the decimal odds of every
+// selection. Unpriced selections, whose Odds are the zero value, are
+// skipped; Engine.Evaluate reports them.
+func (s Slip) CombinedOdds() *big.Rat {
+	combined := big.NewRat(1, 1)
+	for _, sel := range s.Selections {
+		if sel.Odds.IsZero() {
+			continue
+		}
+		combined.Mul(combined, sel.Odds.Decimal())
+	}
+	return combined
+}
+
+// PotentialPayout returns the stake times the combined odds, rounded down to
+// the currency's minor unit.
+func (s Slip) PotentialPayout() (money.Amount, errors.LayerError) {
+	return s.Stake.MulRat(s.CombinedOdds())
+}
+
+// Violation describes one broken rule. SelectionID is empty for rules that
+// apply to the slip as a whole.
+type Violation struct {
+	Rule        string
+	SelectionID string
+	Message     string
+	Details     map[string]any
+}
+
+// Rule inspects a slip and returns every violation it finds.
+type Rule func(slip Slip) []Violation
+
+type Engine struct {
+	rules []Rule
+}
+
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Evaluate runs every rule and returns a BusinessRuleError with code
+// ErrInvalidBusinessRule listing all violations in Details["violations"], or
+// nil when the slip is acceptable. Empty slips and selections without odds
+// are always violations.
+func (e *Engine) Evaluate(slip Slip) errors.LayerError {
+	var violations []Violation
+	if len(slip.Selections) == 0 {
+		violations = append(violations, Violation{Rule: "min_selections", Message: "Bet slip has no selections"})
+	}
+	for _, sel := range slip.Selections {
+		if sel.Odds.IsZero() {
+			violations = append(violations, Violation{
+				Rule:        "unpriced_selection",
+				SelectionID: sel.ID,
+				Message:     fmt.Sprintf("Selection '%s' has no odds", sel.ID),
+			})
+		}
+	}
+	violations = append(violations, runAll(slip, e.rules)...)
+	if len(violations) == 0 {
+		return nil
+	}
+	return errors.NewBusinessRuleError(errors.ErrInvalidBusinessRule,
+		fmt.Sprintf("Bet slip violates %d rule(s)", len(violations)),
+		map[string]any{"violations": describe(violations)})
+}
+
+// Option adapts the engine to a validation option for Slip or *Slip values,
+// so a slip can be checked alongside request fields with validation.Validate.
+func (e *Engine) Option() validation.ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		var slip Slip
+		switch v := value.(type) {
+		case Slip:
+			slip = v
+		case *Slip:
+			if v == nil {
+				return errors.NewValidationError(errors.ErrMissingRequired,
+					fmt.Sprintf("Field '%s' is required", field),
+					map[string]any{"field": field})
+			}
+			slip = *v
+		default:
+			return errors.NewValidationError(errors.ErrInvalidFormat,
+				fmt.Sprintf("Field '%s' must be a bet slip", field),
+				map[string]any{"field": field})
+		}
+		err := e.Evaluate(slip)
+		if err == nil {
+			return nil
+		}
+		err.Details()["field"] = field
+		return err
+	}
+}
+
+func describe(violations []Violation) []map[string]any {
+	out := make([]map[string]any, 0, len(violations))
+	for _, v := range violations {
+		entry := map[string]any{"rule": v.Rule, "message": v.Message}
+		if v.SelectionID != "" {
+			entry["selection_id"] = v.SelectionID
+		}
+		if len(v.Details) > 0 {
+			entry["details"] = v.Details
+		}
+		out = append(out, entry)
+	}
+	return out
+}
@@ -0,0 +1,194 @@
+package betslip
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/odds"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/validation"
+)
+
+func sel(id, event, market, price string) Selection {
+	return Selection{ID: id, EventID: event, Market: market, Odds: odds.MustParse(odds.Decimal, price)}
+}
+
+func slip(stake, jurisdiction string, selections ...Selection) Slip {
+	return Slip{Stake: money.MustParse(stake, "MXN"), Jurisdiction: jurisdiction, Selections: selections}
+}
+
+func newTestEngine() *Engine {
+	return NewEngine(
+		MaxSelections(4),
+		NoCorrelatedSelections(),
+		MaxPayout(money.MustParse("100000", "MXN")),
+		MinCombinedOdds(odds.MustParse(odds.Decimal, "1.50")),
+		ForMarket("player_props", MaxSelections(1)),
+		ForJurisdiction("US-NJ", MinSelectionOdds(odds.MustParse(odds.Decimal, "1.20"))),
+	)
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		slip      Slip
+		wantRules []string
+		wantIDs   []string
+	}{
+		{
+			name:      "Valid accumulator",
+			slip:      slip("100", "MX", sel("s1", "e1", "1x2", "2.00"), sel("s2", "e2", "1x2", "1.80")),
+			wantRules: nil,
+		},
+		{
+			name:      "Empty slip",
+			slip:      slip("100", "MX"),
+			wantRules: []string{"min_selections", "min_combined_odds"},
+			wantIDs:   []string{"", ""},
+		},
+		{
+			name: "Too many selections",
+			slip: slip("10", "MX",
+				sel("s1", "e1", "1x2", "1.50"), sel("s2", "e2", "1x2", "1.50"), sel("s3", "e3", "1x2", "1.50"),
+				sel("s4", "e4", "1x2", "1.50"), sel("s5", "e5", "1x2", "1.50")),
+			wantRules: []string{"max_selections"},
+			wantIDs:   []string{""},
+		},
+		{
+			name:      "Correlated selections",
+			slip:      slip("100", "MX", sel("s1", "e1", "1x2", "2.00"), sel("s2", "e1", "totals", "1.90"), sel("s3", "e1", "btts", "1.70")),
+			wantRules: []string{"correlated_selections", "correlated_selections"},
+			wantIDs:   []string{"s2", "s3"},
+		},
+		{
+			name:      "Payout above maximum",
+			slip:      slip("50000", "MX", sel("s1", "e1", "1x2", "2.50")),
+			wantRules: []string{"max_payout"},
+			wantIDs:   []string{""},
+		},
+		{
+			name:      "Payout at maximum",
+			slip:      slip("40000", "MX", sel("s1", "e1", "1x2", "2.50")),
+			wantRules: nil,
+		},
+		{
+			name:      "Combined odds too low",
+			slip:      slip("100", "MX", sel("s1", "e1", "1x2", "1.20"), sel("s2", "e2", "1x2", "1.10")),
+			wantRules: []string{"min_combined_odds"},
+			wantIDs:   []string{""},
+		},
+		{
+			name:      "Market scoped limit",
+			slip:      slip("100", "MX", sel("s1", "e1", "player_props", "2.00"), sel("s2", "e2", "player_props", "2.00")),
+			wantRules: []string{"max_selections"},
+			wantIDs:   []string{""},
+		},
+		{
+			name:      "Jurisdiction scoped rule applies",
+			slip:      slip("100", "US-NJ", sel("s1", "e1", "1x2", "1.10"), sel("s2", "e2", "1x2", "2.00")),
+			wantRules: []string{"min_selection_odds"},
+			wantIDs:   []string{"s1"},
+		},
+		{
+			name:      "Jurisdiction scoped rule skipped elsewhere",
+			slip:      slip("100", "MX", sel("s1", "e1", "1x2", "1.10"), sel("s2", "e2", "1x2", "2.00")),
+			wantRules: nil,
+		},
+		{
+			name:      "Unpriced selection",
+			slip:      slip("100", "US-NJ", sel("s1", "e1", "1x2", "2.00"), Selection{ID: "s2", EventID: "e2", Market: "1x2"}),
+			wantRules: []string{"unpriced_selection"},
+			wantIDs:   []string{"s2"},
+		},
+	}
+
+	engine := newTestEngine()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := engine.Evaluate(tt.slip)
+			if tt.wantRules == nil {
+				if err != nil {
+					t.Fatalf("Evaluate() unexpected error: %v (%v)", err, err.Details())
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Evaluate() expected violations %v", tt.wantRules)
+			}
+			if err.Code() != errors.ErrInvalidBusinessRule || err.Type() != errors.BusinessRuleError {
+				t.Errorf("error = %v/%v, want %v/%v", err.Code(), err.Type(), errors.ErrInvalidBusinessRule, errors.BusinessRuleError)
+			}
+			violations := err.Details()["violations"].([]map[string]any)
+			var rules, ids []string
+			for _, v := range violations {
+				rules = append(rules, v["rule"].(string))
+				id, _ := v["selection_id"].(string)
+				ids = append(ids, id)
+			}
+			if !reflect.DeepEqual(rules, tt.wantRules) {
+				t.Errorf("rules = %v, want %v", rules, tt.wantRules)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("selection ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestSlip_PotentialPayout(t *testing.T) {
+	s := slip("10", "MX", sel("s1", "e1", "1x2", "1.33"), sel("s2", "e2", "1x2", "2.10"))
+	payout, err := s.PotentialPayout()
+	if err != nil {
+		t.Fatalf("PotentialPayout() unexpected error: %v", err)
+	}
+	// 10 * 1.33 * 2.10 = 27.93 exactly.
+	if payout.String() != "27.93" {
+		t.Errorf("PotentialPayout() = %s, want 27.93", payout)
+	}
+}
+
+func TestEngine_Option(t *testing.T) {
+	s := slip("100", "MX", sel("s1", "e1", "1x2", "2.00"), sel("s2", "e1", "1x2", "1.80"))
+	err := validation.Validate(
+		validation.Field("stake", s.Stake, validation.PositiveAmount("MXN")),
+		validation.Field("slip", &s, newTestEngine().Option()),
+	)
+	if err == nil {
+		t.Fatal("Expected correlated selections to be rejected")
+	}
+	if err.Details()["field"] != "slip" {
+		t.Errorf("Details[field] = %v, want slip", err.Details()["field"])
+	}
+}
+
+func TestEngine_Option_Values(t *testing.T) {
+	valid := slip("100", "MX", sel("s1", "e1", "1x2", "2.00"))
+	var nilSlip *Slip
+	tests := []struct {
+		name     string
+		value    any
+		wantCode errors.ErrorCode
+	}{
+		{name: "Slip", value: valid},
+		{name: "Pointer to slip", value: &valid},
+		{name: "Nil pointer", value: nilSlip, wantCode: errors.ErrMissingRequired},
+		{name: "Other type", value: "slip", wantCode: errors.ErrInvalidFormat},
+	}
+
+	option := newTestEngine().Option()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := option("slip", tt.value)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("Option() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Code() != tt.wantCode {
+				t.Fatalf("Option() = %v, want code %v", err, tt.wantCode)
+			}
+		})
+	}
+}
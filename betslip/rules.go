@@ -0,0 +1,148 @@
+package betslip
+
+import (
+	"fmt"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/odds"
+)
+
+// MaxSelections limits the number of selections on the slip.
+func MaxSelections(n int) Rule {
+	return func(slip Slip) []Violation {
+		if len(slip.Selections) <= n {
+			return nil
+		}
+		return []Violation{{
+			Rule:    "max_selections",
+			Message: fmt.Sprintf("Bet slip allows at most %d selections", n),
+			Details: map[string]any{"max": n, "actual": len(slip.Selections)},
+		}}
+	}
+}
+
+// NoCorrelatedSelections rejects slips with more than one selection from the
+// same event. Every selection after the first of an event is reported.
+func NoCorrelatedSelections() Rule {
+	return func(slip Slip) []Violation {
+		first := make(map[string]string)
+		var violations []Violation
+		for _, sel := range slip.Selections {
+			id, seen := first[sel.EventID]
+			if !seen {
+				first[sel.EventID] = sel.ID
+				continue
+			}
+			violations = append(violations, Violation{
+				Rule:        "correlated_selections",
+				SelectionID: sel.ID,
+				Message:     fmt.Sprintf("Selection '%s' is correlated with '%s' on event '%s'", sel.ID, id, sel.EventID),
+				Details:     map[string]any{"event_id": sel.EventID, "correlated_with": id},
+			})
+		}
+		return violations
+	}
+}
+
+// MaxPayout limits the potential payout of the slip. Slips in another
+// currency are reported as violations rather than compared.
+func MaxPayout(max money.Amount) Rule {
+	return func(slip Slip) []Violation {
+		payout, err := slip.PotentialPayout()
+		if err != nil {
+			return []Violation{{Rule: "max_payout", Message: err.Error(), Details: err.Details()}}
+		}
+		cmp, err := payout.Cmp(max)
+		if err != nil {
+			return []Violation{{Rule: "max_payout", Message: err.Error(), Details: err.Details()}}
+		}
+		if cmp <= 0 {
+			return nil
+		}
+		return []Violation{{
+			Rule:    "max_payout",
+			Message: fmt.Sprintf("Potential payout %s exceeds the maximum of %s %s", payout, max, max.Currency()),
+			Details: map[string]any{"max": max.String(), "payout": payout.String(), "currency": max.Currency()},
+		}}
+	}
+}
+
+// MinCombinedOdds requires the product of the selection odds to be at least
+// min.
+func MinCombinedOdds(min odds.Odds) Rule {
+	return func(slip Slip) []Violation {
+		combined := slip.CombinedOdds()
+		if combined.Cmp(min.Decimal()) >= 0 {
+			return nil
+		}
+		return []Violation{{
+			Rule:    "min_combined_odds",
+			Message: fmt.Sprintf("Combined odds %s are below the minimum of %s", combined.FloatString(2), min),
+			Details: map[string]any{"min": min.String(), "actual": combined.FloatString(2)},
+		}}
+	}
+}
+
+// MinSelectionOdds requires every selection to be priced at least min.
+// Unpriced selections are left to Engine.Evaluate.
+func MinSelectionOdds(min odds.Odds) Rule {
+	return func(slip Slip) []Violation {
+		var violations []Violation
+		for _, sel := range slip.Selections {
+			if sel.Odds.IsZero() || sel.Odds.Cmp(min) >= 0 {
+				continue
+			}
+			violations = append(violations, Violation{
+				Rule:        "min_selection_odds",
+				SelectionID: sel.ID,
+				Message:     fmt.Sprintf("Selection '%s' odds %s are below the minimum of %s", sel.ID, sel.Odds, min),
+				Details:     map[string]any{"min": min.String(), "actual": sel.Odds.String()},
+			})
+		}
+		return violations
+	}
+}
+
+// ForMarket runs the rules against the selections of one market only, so a
+// rule such as MaxSelections(2) limits the legs taken from that market. The
+// rules are skipped when the slip has no selection in the market.
+func ForMarket(market string, rules ...Rule) Rule {
+	return func(slip Slip) []Violation {
+		scoped := slip
+		scoped.Selections = nil
+		for _, sel := range slip.Selections {
+			if sel.Market == market {
+				scoped.Selections = append(scoped.Selections, sel)
+			}
+		}
+		if len(scoped.Selections) == 0 {
+			return nil
+		}
+		violations := runAll(scoped, rules)
+		for i := range violations {
+			if violations[i].Details == nil {
+				violations[i].Details = map[string]any{}
+			}
+			violations[i].Details["market"] = market
+		}
+		return violations
+	}
+}
+
+// ForJurisdiction runs the rules only for slips placed in the jurisdiction.
+func ForJurisdiction(jurisdiction string, rules ...Rule) Rule {
+	return func(slip Slip) []Violation {
+		if slip.Jurisdiction != jurisdiction {
+			return nil
+		}
+		return runAll(slip, rules)
+	}
+}
+
+func runAll(slip Slip, rules []Rule) []Violation {
+	var violations []Violation
+	for _, rule := range rules {
+		violations = append(violations, rule(slip)...)
+	}
+	return violations
+}
@@ -0,0 +1,282 @@
+package circuitbreaker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+type State string
+
+const (
+	Closed   State = "closed"
+	Open     State = "open"
+	HalfOpen State = "half_open"
+)
+
+// Settings configures a Breaker. Zero values take the documented defaults.
+type Settings struct {
+	Name string
+	// Window is the rolling period over which the failure ratio is computed,
+	// split into Buckets. Defaults to 60s and 10 buckets, and is raised to
+	// one nanosecond per bucket if shorter.
+	Window  time.Duration
+	Buckets int
+	// MinRequests is the number of calls in the window below which the
+	// circuit never opens. Defaults to 20.
+	MinRequests int
+	// FailureRatio opens the circuit when failures/calls reaches it. Defaults
+	// to 0.5.
+	FailureRatio float64
+	// OpenTimeout is how long the circuit stays open before letting probes
+	// through. Defaults to 30s.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of calls allowed while half-open; the
+	// circuit closes when all of them succeed. Defaults to 1.
+	HalfOpenProbes int
+	// IsFailure decides which errors count as failures. Defaults to errors
+	// from the infrastructure layer, so validation, business rule and other
+	// client errors never open the circuit. ErrRequestCanceled and
+	// ErrCircuitOpen are excluded: a caller giving up or another breaker
+	// failing fast says nothing about the dependency.
+	IsFailure func(err errors.LayerError) bool
+	// OnStateChange is called after every transition, outside the breaker's
+	// lock.
+	OnStateChange func(name string, from, to State)
+	// Clock defaults to clock.System().
+	Clock clock.Clock
+}
+
+// Breaker is a circuit breaker. It is safe for concurrent use.
+type Breaker struct {
+	settings Settings
+
+	mu         sync.Mutex
+	state      State
+	generation uint64
+	openedAt   time.Time
+	probes     int
+	successes  int
+	buckets    []bucket
+}
+
+type bucket struct {
+	start    time.Time
+	calls    int
+	failures int
+}
+
+func New(s Settings) *Breaker {
+	if s.Window <= 0 {
+		s.Window = time.Minute
+	}
+	if s.Buckets <= 0 {
+		s.Buckets = 10
+	}
+	if s.Window < time.Duration(s.Buckets) {
+		// Buckets are at least 1ns long.
+		s.Window = time.Duration(s.Buckets)
+	}
+	if s.MinRequests <= 0 {
+		s.MinRequests = 20
+	}
+	if s.FailureRatio <= 0 {
+		s.FailureRatio = 0.5
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = 30 * time.Second
+	}
+	if s.HalfOpenProbes <= 0 {
+		s.HalfOpenProbes = 1
+	}
+	if s.IsFailure == nil {
+		s.IsFailure = isFailure
+	}
+	if s.Clock == nil {
+		s.Clock = clock.System()
+	}
+	return &Breaker{settings: s, state: Closed, buckets: make([]bucket, s.Buckets)}
+}
+
+func isFailure(err errors.LayerError) bool {
+	switch err.Code() {
+	case errors.ErrRequestCanceled, errors.ErrCircuitOpen:
+		return false
+	}
+	return err.Layer() == errors.InfrastructureLayer
+}
+
+// Execute runs fn unless the circuit is open, in which case it fails fast
+// with an infrastructure error coded ErrCircuitOpen whose details carry the
+// circuit name and "retry_after" in seconds. A panic in fn counts as a
+// failure and is re-raised.
+func (b *Breaker) Execute(ctx context.Context, fn func(ctx context.Context) errors.LayerError) errors.LayerError {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	failed := true
+	defer func() {
+		if failed {
+			done(errors.NewInfrastructureError(errors.ErrExternalService, "Call panicked"))
+		}
+	}()
+	result := fn(ctx)
+	failed = false
+	done(result)
+	return result
+}
+
+// Allow reserves a call for callers that cannot wrap it in a function. The
+// returned done must be called exactly once with the call's error.
+func (b *Breaker) Allow() (done func(err errors.LayerError), err errors.LayerError) {
+	b.mu.Lock()
+	now := b.settings.Clock.Now()
+	changes := b.refresh(now)
+	var rejected errors.LayerError
+	switch {
+	case b.state == Open:
+		rejected = b.openError(now)
+	case b.state == HalfOpen && b.probes >= b.settings.HalfOpenProbes:
+		rejected = b.openError(now)
+	case b.state == HalfOpen:
+		b.probes++
+	}
+	generation := b.generation
+	b.mu.Unlock()
+	b.notify(changes)
+	if rejected != nil {
+		return nil, rejected
+	}
+	var once sync.Once
+	return func(err errors.LayerError) {
+		once.Do(func() { b.record(generation, err != nil && b.settings.IsFailure(err)) })
+	}, nil
+}
+
+// State returns the current state, moving an expired open circuit to
+// half-open.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	changes := b.refresh(b.settings.Clock.Now())
+	state := b.state
+	b.mu.Unlock()
+	b.notify(changes)
+	return state
+}
+
+func (b *Breaker) record(generation uint64, failed bool) {
+	b.mu.Lock()
+	now := b.settings.Clock.Now()
+	changes := b.refresh(now)
+	// Results of calls admitted before the last transition are stale.
+	if generation != b.generation {
+		b.mu.Unlock()
+		b.notify(changes)
+		return
+	}
+	switch b.state {
+	case Closed:
+		bk := b.bucket(now)
+		bk.calls++
+		if failed {
+			bk.failures++
+		}
+		calls, failures := b.totals(now)
+		if calls >= b.settings.MinRequests && float64(failures)/float64(calls) >= b.settings.FailureRatio {
+			changes = append(changes, b.transition(Open, now))
+		}
+	case HalfOpen:
+		if failed {
+			changes = append(changes, b.transition(Open, now))
+		} else if b.successes++; b.successes >= b.settings.HalfOpenProbes {
+			changes = append(changes, b.transition(Closed, now))
+		}
+	}
+	b.mu.Unlock()
+	b.notify(changes)
+}
+
+// refresh moves an open circuit to half-open once its timeout has passed.
+func (b *Breaker) refresh(now time.Time) []change {
+	if b.state == Open && !now.Before(b.openedAt.Add(b.settings.OpenTimeout)) {
+		return []change{b.transition(HalfOpen, now)}
+	}
+	return nil
+}
+
+type change struct{ from, to State }
+
+func (b *Breaker) transition(to State, now time.Time) change {
+	c := change{from: b.state, to: to}
+	b.state = to
+	b.generation++
+	b.probes, b.successes = 0, 0
+	switch to {
+	case Open:
+		b.openedAt = now
+	case Closed:
+		b.buckets = make([]bucket, b.settings.Buckets)
+	}
+	return c
+}
+
+func (b *Breaker) notify(changes []change) {
+	if b.settings.OnStateChange == nil {
+		return
+	}
+	for _, c := range changes {
+		b.settings.OnStateChange(b.settings.Name, c.from, c.to)
+	}
+}
+
+func (b *Breaker) bucketSize() time.Duration {
+	return b.settings.Window / time.Duration(b.settings.Buckets)
+}
+
+// bucket returns the bucket for now, resetting it if it holds an older period.
+func (b *Breaker) bucket(now time.Time) *bucket {
+	size := b.bucketSize()
+	start := now.Truncate(size)
+	// Before 1970 the bucket number is negative; keep the index in range.
+	i := int(start.UnixNano() / int64(size) % int64(len(b.buckets)))
+	if i < 0 {
+		i += len(b.buckets)
+	}
+	bk := &b.buckets[i]
+	if !bk.start.Equal(start) {
+		*bk = bucket{start: start}
+	}
+	return bk
+}
+
+func (b *Breaker) totals(now time.Time) (calls, failures int) {
+	oldest := now.Truncate(b.bucketSize()).Add(-b.settings.Window)
+	for _, bk := range b.buckets {
+		if bk.start.After(oldest) {
+			calls += bk.calls
+			failures += bk.failures
+		}
+	}
+	return calls, failures
+}
+
+func (b *Breaker) openError(now time.Time) errors.LayerError {
+	retry := b.openedAt.Add(b.settings.OpenTimeout).Sub(now)
+	if b.state == HalfOpen {
+		// Probes are in flight; their outcome is expected shortly.
+		retry = time.Second
+	}
+	return errors.NewInfrastructureError(errors.ErrCircuitOpen,
+		fmt.Sprintf("Circuit '%s' is open", b.settings.Name),
+		map[string]any{
+			"circuit":     b.settings.Name,
+			"state":       string(b.state),
+			"retry_after": int64(math.Ceil(retry.Seconds())),
+		})
+}
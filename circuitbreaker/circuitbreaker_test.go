@@ -0,0 +1,203 @@
+package circuitbreaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+var (
+	infraErr    = errors.NewInfrastructureError(errors.ErrExternalService, "Odds provider unavailable")
+	businessErr = errors.NewBusinessRuleError(errors.ErrInvalidBusinessRule, "Market suspended")
+	canceledErr = errors.NewInfrastructureError(errors.ErrRequestCanceled, "Operation was canceled")
+	openErr     = errors.NewInfrastructureError(errors.ErrCircuitOpen, "Circuit 'wallet' is open")
+)
+
+type transition struct{ from, to State }
+
+func newTestBreaker(t *testing.T, s Settings) (*Breaker, *clock.Fake, *[]transition) {
+	t.Helper()
+	fake := clock.NewFake(time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC))
+	var changes []transition
+	s.Name = "odds"
+	s.Clock = fake
+	s.OnStateChange = func(name string, from, to State) {
+		if name != "odds" {
+			t.Errorf("OnStateChange name = %q, want odds", name)
+		}
+		changes = append(changes, transition{from, to})
+	}
+	return New(s), fake, &changes
+}
+
+func call(b *Breaker, err errors.LayerError) errors.LayerError {
+	return b.Execute(context.Background(), func(context.Context) errors.LayerError { return err })
+}
+
+func TestBreaker_Opens(t *testing.T) {
+	tests := []struct {
+		name      string
+		results   []errors.LayerError
+		wantState State
+	}{
+		{"Below minimum requests", []errors.LayerError{infraErr, infraErr, infraErr}, Closed},
+		{"Ratio reached", []errors.LayerError{nil, infraErr, nil, infraErr}, Open},
+		{"Ratio not reached", []errors.LayerError{nil, nil, nil, infraErr}, Closed},
+		{"Business errors are not failures", []errors.LayerError{businessErr, businessErr, businessErr, businessErr}, Closed},
+		{"Cancellations are not failures", []errors.LayerError{canceledErr, canceledErr, canceledErr, canceledErr}, Closed},
+		{"Open nested circuits are not failures", []errors.LayerError{openErr, openErr, openErr, openErr}, Closed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _, _ := newTestBreaker(t, Settings{MinRequests: 4, FailureRatio: 0.5})
+			for _, r := range tt.results {
+				_ = call(b, r)
+			}
+			if got := b.State(); got != tt.wantState {
+				t.Errorf("State() = %v, want %v", got, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestBreaker_FailsFastWhenOpen(t *testing.T) {
+	b, fake, _ := newTestBreaker(t, Settings{MinRequests: 1, OpenTimeout: 30 * time.Second})
+	_ = call(b, infraErr)
+
+	fake.Advance(10 * time.Second)
+	ran := false
+	err := b.Execute(context.Background(), func(context.Context) errors.LayerError {
+		ran = true
+		return nil
+	})
+	if ran {
+		t.Error("Expected open circuit not to run the call")
+	}
+	if err == nil || err.Code() != errors.ErrCircuitOpen || err.Layer() != errors.InfrastructureLayer {
+		t.Fatalf("Execute() error = %v, want %s", err, errors.ErrCircuitOpen)
+	}
+	if err.Details()["retry_after"] != int64(20) || err.Details()["circuit"] != "odds" {
+		t.Errorf("Details = %v, want retry_after 20 and circuit odds", err.Details())
+	}
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	tests := []struct {
+		name        string
+		probe       errors.LayerError
+		wantState   State
+		wantChanges []transition
+	}{
+		{"Successful probe closes", nil, Closed, []transition{{Closed, Open}, {Open, HalfOpen}, {HalfOpen, Closed}}},
+		{"Failed probe reopens", infraErr, Open, []transition{{Closed, Open}, {Open, HalfOpen}, {HalfOpen, Open}}},
+		{"Business error probe closes", businessErr, Closed, []transition{{Closed, Open}, {Open, HalfOpen}, {HalfOpen, Closed}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, fake, changes := newTestBreaker(t, Settings{MinRequests: 1, OpenTimeout: 30 * time.Second})
+			_ = call(b, infraErr)
+			fake.Advance(30 * time.Second)
+
+			_ = call(b, tt.probe)
+			if got := b.State(); got != tt.wantState {
+				t.Errorf("State() = %v, want %v", got, tt.wantState)
+			}
+			if len(*changes) != len(tt.wantChanges) {
+				t.Fatalf("changes = %v, want %v", *changes, tt.wantChanges)
+			}
+			for i, c := range tt.wantChanges {
+				if (*changes)[i] != c {
+					t.Errorf("changes[%d] = %v, want %v", i, (*changes)[i], c)
+				}
+			}
+		})
+	}
+}
+
+func TestBreaker_HalfOpenLimitsProbes(t *testing.T) {
+	b, fake, _ := newTestBreaker(t, Settings{MinRequests: 1, OpenTimeout: time.Second, HalfOpenProbes: 1})
+	_ = call(b, infraErr)
+	fake.Advance(time.Second)
+
+	done, err := b.Allow()
+	if err != nil {
+		t.Fatalf("Allow() first probe unexpected error: %v", err)
+	}
+	if _, err := b.Allow(); err == nil || err.Code() != errors.ErrCircuitOpen {
+		t.Errorf("Allow() second probe error = %v, want %s", err, errors.ErrCircuitOpen)
+	}
+	done(nil)
+	if b.State() != Closed {
+		t.Errorf("State() = %v, want %v", b.State(), Closed)
+	}
+}
+
+func TestBreaker_RollingWindow(t *testing.T) {
+	b, fake, _ := newTestBreaker(t, Settings{MinRequests: 4, FailureRatio: 0.5, Window: time.Minute, Buckets: 6})
+	_ = call(b, infraErr)
+	_ = call(b, infraErr)
+	_ = call(b, infraErr)
+
+	// The failures age out of the window before the fourth call.
+	fake.Advance(2 * time.Minute)
+	_ = call(b, infraErr)
+	if got := b.State(); got != Closed {
+		t.Errorf("State() = %v, want %v", got, Closed)
+	}
+}
+
+func TestBreaker_EdgeClocks(t *testing.T) {
+	tests := []struct {
+		name   string
+		now    time.Time
+		window time.Duration
+	}{
+		{"Window shorter than buckets", time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC), 5 * time.Nanosecond},
+		{"Before 1970", time.Date(1969, 12, 31, 23, 59, 59, 0, time.UTC), time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(Settings{Window: tt.window, MinRequests: 2, Clock: clock.NewFake(tt.now)})
+			_ = call(b, infraErr)
+			_ = call(b, infraErr)
+			if got := b.State(); got != Open {
+				t.Errorf("State() = %v, want %v", got, Open)
+			}
+		})
+	}
+}
+
+func TestBreaker_CustomPredicate(t *testing.T) {
+	b, _, _ := newTestBreaker(t, Settings{
+		MinRequests: 1,
+		IsFailure:   func(err errors.LayerError) bool { return err.Code() == errors.ErrInvalidBusinessRule },
+	})
+	_ = call(b, infraErr)
+	if b.State() != Closed {
+		t.Fatalf("State() = %v, want %v", b.State(), Closed)
+	}
+	_ = call(b, businessErr)
+	if b.State() != Open {
+		t.Errorf("State() = %v, want %v", b.State(), Open)
+	}
+}
+
+func TestBreaker_PanicCountsAsFailure(t *testing.T) {
+	b, _, _ := newTestBreaker(t, Settings{MinRequests: 1})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Expected panic to propagate")
+			}
+		}()
+		_ = b.Execute(context.Background(), func(context.Context) errors.LayerError { panic("boom") })
+	}()
+	if b.State() != Open {
+		t.Errorf("State() = %v, want %v", b.State(), Open)
+	}
+}
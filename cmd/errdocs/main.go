@@ -0,0 +1,65 @@
+// Command errdocs renders the error catalog as a static Markdown or HTML
+// page with one anchored section per code, describing its type, layer,
+// HTTP and gRPC mapping, retryability and remediation.
+//
+// Usage:
+//
+//	go run ./cmd/errdocs -format html -o docs/errors.html
+//
+// A code's section is at "#" plus the lower-case code with hyphens, such as
+// errors.html#repository-operation, so problem-details type URIs can link
+// to it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/protocols"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "errdocs:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("errdocs", flag.ContinueOnError)
+	format := fs.String("format", "markdown", "output format: markdown or html")
+	out := fs.String("o", "", "output file (default stdout)")
+	title := fs.String("title", "Error Codes", "page title")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var render func(io.Writer, string, []entry) error
+	switch *format {
+	case "markdown", "md":
+		render = renderMarkdown
+	case "html":
+		render = renderHTML
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	list := entries(errors.Catalog(), protocols.NewDefaultHTTPErrorHandler(), protocols.NewDefaultGRPCErrorHandler())
+
+	if *out == "" {
+		return render(stdout, *title, list)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := render(f, *title, list); err != nil {
+		f.Close()
+		return err
+	}
+	// Close reports write-back errors, such as a full disk.
+	return f.Close()
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/protocols"
+)
+
+// entry is a catalog definition with its protocol mappings.
+type entry struct {
+	errors.Definition
+	Anchor     string
+	HTTPStatus int
+	HTTPText   string
+	GRPCCode   int
+	GRPCName   string
+	Example    string
+}
+
+// grpcNames are the names of the codes in google.golang.org/grpc/codes.
+var grpcNames = map[int]string{
+	0: "OK", 1: "Canceled", 2: "Unknown", 3: "InvalidArgument", 4: "DeadlineExceeded",
+	5: "NotFound", 6: "AlreadyExists", 7: "PermissionDenied", 8: "ResourceExhausted",
+	9: "FailedPrecondition", 10: "Aborted", 11: "OutOfRange", 12: "Unimplemented",
+	13: "Internal", 14: "Unavailable", 15: "DataLoss", 16: "Unauthenticated",
+}
+
+// anchor is the fragment identifying code in the catalog, such as
+// "repository-operation" for REPOSITORY_OPERATION.
+func anchor(code errors.ErrorCode) string {
+	return strings.ToLower(strings.ReplaceAll(string(code), "_", "-"))
+}
+
+// entries maps each definition with the HTTP and gRPC handlers.
+func entries(defs []errors.Definition, httpHandler protocols.HTTPErrorHandler, grpcHandler protocols.GRPCErrorHandler) []entry {
+	out := make([]entry, 0, len(defs))
+	for _, d := range defs {
+		err := d.Example()
+		httpResp := httpHandler.HandleHTTPError(err)
+		grpcResp := grpcHandler.HandleGRPCError(err)
+		example, _ := json.MarshalIndent(httpResp.ProtocolResponse, "", "  ")
+		out = append(out, entry{
+			Definition: d,
+			Anchor:     anchor(d.Code),
+			HTTPStatus: httpResp.HTTPStatus,
+			HTTPText:   protocols.StatusText(httpResp.HTTPStatus),
+			GRPCCode:   grpcResp.GRPCCode,
+			GRPCName:   grpcNames[grpcResp.GRPCCode],
+			Example:    string(example),
+		})
+	}
+	return out
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
+// renderMarkdown writes an index table followed by one section per code.
+// Each section starts with an explicit anchor so links survive heading
+// changes.
+func renderMarkdown(w io.Writer, title string, list []entry) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	b.WriteString("| Code | Type | HTTP | gRPC | Retryable |\n")
+	b.WriteString("|------|------|------|------|-----------|\n")
+	for _, e := range list {
+		fmt.Fprintf(&b, "| [`%s`](#%s) | %s | %d | %s | %s |\n",
+			e.Code, e.Anchor, e.Type, e.HTTPStatus, e.GRPCName, yesNo(e.Retryable))
+	}
+	for _, e := range list {
+		fmt.Fprintf(&b, "\n<a id=\"%s\"></a>\n\n## %s\n\n%s\n\n", e.Anchor, e.Code, e.Description)
+		b.WriteString("| | |\n|---|---|\n")
+		fmt.Fprintf(&b, "| Type | `%s` |\n", e.Type)
+		fmt.Fprintf(&b, "| Layer | `%s` |\n", e.Layer)
+		fmt.Fprintf(&b, "| HTTP status | %d %s |\n", e.HTTPStatus, e.HTTPText)
+		fmt.Fprintf(&b, "| gRPC code | %d %s |\n", e.GRPCCode, e.GRPCName)
+		fmt.Fprintf(&b, "| Retryable | %s |\n", yesNo(e.Retryable))
+		if e.Remediation != "" {
+			fmt.Fprintf(&b, "\n**Remediation:** %s\n", e.Remediation)
+		}
+		fmt.Fprintf(&b, "\n```json\n%s\n```\n", e.Example)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlTemplate = template.Must(template.New("errdocs").Funcs(template.FuncMap{"yesNo": yesNo}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25rem 0.5rem; text-align: left; }
+pre { background: #f6f8fa; padding: 0.75rem; overflow-x: auto; }
+section:target { background: #fffbdd; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<thead><tr><th>Code</th><th>Type</th><th>HTTP</th><th>gRPC</th><th>Retryable</th></tr></thead>
+<tbody>
+{{- range .Entries}}
+<tr><td><a href="#{{.Anchor}}"><code>{{.Code}}</code></a></td><td>{{.Type}}</td><td>{{.HTTPStatus}}</td><td>{{.GRPCName}}</td><td>{{yesNo .Retryable}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- range .Entries}}
+<section id="{{.Anchor}}">
+<h2><a href="#{{.Anchor}}">{{.Code}}</a></h2>
+<p>{{.Description}}</p>
+<table>
+<tr><th>Type</th><td><code>{{.Type}}</code></td></tr>
+<tr><th>Layer</th><td><code>{{.Layer}}</code></td></tr>
+<tr><th>HTTP status</th><td>{{.HTTPStatus}} {{.HTTPText}}</td></tr>
+<tr><th>gRPC code</th><td>{{.GRPCCode}} {{.GRPCName}}</td></tr>
+<tr><th>Retryable</th><td>{{yesNo .Retryable}}</td></tr>
+</table>
+{{- if .Remediation}}
+<p><strong>Remediation:</strong> {{.Remediation}}</p>
+{{- end}}
+<pre><code>{{.Example}}</code></pre>
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+// renderHTML writes a standalone page with the same content as
+// renderMarkdown. Each code is a section whose id is its anchor.
+func renderHTML(w io.Writer, title string, list []entry) error {
+	return htmlTemplate.Execute(w, struct {
+		Title   string
+		Entries []entry
+	}{title, list})
+}
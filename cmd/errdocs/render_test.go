@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+func TestAnchor(t *testing.T) {
+	if got := anchor(errors.ErrRepositoryOperation); got != "repository-operation" {
+		t.Errorf("anchor() = %q, want repository-operation", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "Markdown",
+			args: nil,
+			want: []string{
+				"# Error Codes",
+				"| [`REPOSITORY_OPERATION`](#repository-operation) | infrastructure | 424 | Unavailable | Yes |",
+				"<a id=\"repository-operation\"></a>",
+				"| gRPC code | 14 Unavailable |",
+				"**Remediation:** Nothing was changed",
+				"\"code\": \"REPOSITORY_OPERATION\"",
+				"| HTTP status | 499 Client Closed Request |",
+			},
+		},
+		{
+			name: "HTML",
+			args: []string{"-format", "html", "-title", "Betmates <errors>"},
+			want: []string{
+				"<title>Betmates &lt;errors&gt;</title>",
+				"<a href=\"#rate-limited\"><code>RATE_LIMITED</code></a>",
+				"<section id=\"rate-limited\">",
+				"<tr><th>HTTP status</th><td>429 Too Many Requests</td></tr>",
+				"<tr><th>gRPC code</th><td>8 ResourceExhausted</td></tr>",
+				"&#34;code&#34;: &#34;RATE_LIMITED&#34;",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := run(tt.args, &out); err != nil {
+				t.Fatalf("run() error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output does not contain %q", want)
+				}
+			}
+			for _, d := range errors.Catalog() {
+				if !strings.Contains(out.String(), anchor(d.Code)) {
+					t.Errorf("output has no anchor for %s", d.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_OutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.md")
+	var out bytes.Buffer
+	if err := run([]string{"-o", path}, &out); err != nil {
+		t.Fatalf("run() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 || !bytes.HasPrefix(data, []byte("# Error Codes")) {
+		t.Errorf("stdout = %q, file starts with %q", out.String(), data[:min(len(data), 20)])
+	}
+}
+
+func TestRun_UnknownFormat(t *testing.T) {
+	if err := run([]string{"-format", "pdf"}, &bytes.Buffer{}); err == nil {
+		t.Error("run() expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,291 @@
+package errors
+
+import (
+	"sort"
+	"sync"
+)
+
+// Definition documents an error code for API consumers: the type and layer
+// of the errors that carry it, what it means, whether retrying the same
+// request can succeed, what the caller should do instead, and an example
+// message and details.
+type Definition struct {
+	Code        ErrorCode
+	Type        ErrorType
+	Layer       LayerType
+	Description string
+	Retryable   bool
+	Remediation string
+	Message     string
+	Details     map[string]interface{}
+}
+
+// Example builds an error matching the definition, for documentation and
+// tests.
+func (d Definition) Example() LayerError {
+	switch d.Layer {
+	case InfrastructureLayer:
+		return NewInfrastructureError(d.Code, d.Message, d.Details)
+	case DomainLayer:
+		return NewDomainError(d.Code, d.Type, d.Message, d.Details)
+	default:
+		return NewApplicationError(d.Code, d.Type, d.Message, d.Details)
+	}
+}
+
+var catalog = struct {
+	sync.RWMutex
+	defs map[ErrorCode]Definition
+}{defs: map[ErrorCode]Definition{}}
+
+// Register adds definitions to the catalog, replacing any with the same
+// code. Services register their own codes at start-up.
+func Register(defs ...Definition) {
+	catalog.Lock()
+	defer catalog.Unlock()
+	for _, d := range defs {
+		catalog.defs[d.Code] = d
+	}
+}
+
+// Lookup returns the definition of code.
+func Lookup(code ErrorCode) (Definition, bool) {
+	catalog.RLock()
+	defer catalog.RUnlock()
+	d, ok := catalog.defs[code]
+	return d, ok
+}
+
+// Catalog returns every registered definition, sorted by code.
+func Catalog() []Definition {
+	catalog.RLock()
+	defer catalog.RUnlock()
+	defs := make([]Definition, 0, len(catalog.defs))
+	for _, d := range catalog.defs {
+		defs = append(defs, d)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Code < defs[j].Code })
+	return defs
+}
+
+func validationCode(code ErrorCode, description, remediation, message string, details map[string]interface{}) Definition {
+	return Definition{Code: code, Type: ValidationError, Layer: ApplicationLayer,
+		Description: description, Remediation: remediation, Message: message, Details: details}
+}
+
+func init() {
+	Register(
+		// Validation Errors
+		validationCode(ErrInvalidEmail, "A field is not a valid email address.",
+			"Correct the address in the field named by details.field.",
+			"Field 'email' must be a valid email", map[string]interface{}{"field": "email", "value": "punter@"}),
+		validationCode(ErrInvalidPassword, "A password does not meet the password policy. Details list every unmet requirement.",
+			"Choose a password that meets every requirement in details.requirements.",
+			"Password does not meet the policy", map[string]interface{}{"field": "password", "requirements": []string{"min_length", "digit"}}),
+		validationCode(ErrMissingRequired, "A required field is missing or blank.",
+			"Send a value for the field named by details.field.",
+			"Field 'email' is required", map[string]interface{}{"field": "email"}),
+		validationCode(ErrInvalidFormat, "A field has the wrong type, length, range or shape.",
+			"Correct the field named by details.field; the other details give the expected bounds.",
+			"Field 'nickname' must have at least 3 characters", map[string]interface{}{"field": "nickname", "min": 3}),
+		validationCode(ErrInvalidDate, "A date is malformed or outside the allowed range.",
+			"Send an RFC 3339 date within the range given in the message.",
+			"Field 'kickoff' must be before 2030-01-01T00:00:00Z", map[string]interface{}{"field": "kickoff"}),
+		validationCode(ErrUnderage, "The person is younger than the legal age of their jurisdiction.",
+			"The customer cannot register or bet until they reach details.min_age. Do not retry.",
+			"Field 'birth_date' must be at least 18 years ago", map[string]interface{}{"field": "birth_date", "min_age": 18, "jurisdiction": "MX"}),
+		validationCode(ErrInvalidUUID, "A field is not a valid UUID.",
+			"Send the canonical 36-character UUID of the resource.",
+			"Field 'market_id' must be a valid UUID", map[string]interface{}{"field": "market_id", "value": "abc"}),
+		validationCode(ErrInvalidURL, "A field is not an absolute URL with an allowed scheme.",
+			"Send an absolute https URL.",
+			"Field 'callback_url' must be a valid URL", map[string]interface{}{"field": "callback_url", "value": "ftp://example.com"}),
+		validationCode(ErrInvalidPhone, "A field is not an E.164 phone number.",
+			"Send the number with its country code, such as +525512345678.",
+			"Field 'phone' must be an E.164 phone number", map[string]interface{}{"field": "phone", "value": "5512345678"}),
+		validationCode(ErrInvalidCountry, "A field is not an ISO 3166-1 alpha-2 country code.",
+			"Send a two-letter upper-case country code, such as MX.",
+			"Field 'country' must be an ISO 3166-1 alpha-2 country code", map[string]interface{}{"field": "country", "value": "XX"}),
+		validationCode(ErrInvalidCurrency, "A field is not an ISO 4217 currency code, or an amount is in the wrong currency.",
+			"Send a three-letter currency code supported by the account, such as MXN.",
+			"Field 'currency' must be an ISO 4217 currency code", map[string]interface{}{"field": "currency", "value": "XYZ"}),
+		validationCode(ErrInvalidIP, "A field is not a valid IP address.",
+			"Send an IPv4 or IPv6 address without a port.",
+			"Field 'ip' must be a valid IP address", map[string]interface{}{"field": "ip", "value": "300.1.1.1"}),
+		validationCode(ErrInvalidCIDR, "A field is not a valid CIDR block.",
+			"Send an address and prefix length, such as 10.0.0.0/8.",
+			"Field 'allow_list' must be a valid CIDR block", map[string]interface{}{"field": "allow_list", "value": "10.0.0.0/33"}),
+		validationCode(ErrInvalidSlug, "A field is not a lower-case slug.",
+			"Use lower-case letters, digits and single hyphens only.",
+			"Field 'slug' must be a valid slug", map[string]interface{}{"field": "slug", "value": "Liga MX"}),
+		validationCode(ErrInvalidBase64, "A field is not valid base64.",
+			"Encode the content with standard padded base64.",
+			"Field 'document' must be valid base64", map[string]interface{}{"field": "document"}),
+		validationCode(ErrInvalidHex, "A field is not a hexadecimal string.",
+			"Send only the characters 0-9 and a-f.",
+			"Field 'seed' must be a hexadecimal string", map[string]interface{}{"field": "seed", "value": "xyz"}),
+		validationCode(ErrInvalidAmount, "A field is not a valid amount for its currency.",
+			"Send a positive decimal string with no more decimals than the currency allows.",
+			"Field 'stake' must be a valid MXN amount", map[string]interface{}{"field": "stake", "currency": "MXN"}),
+		validationCode(ErrMalformedRequest, "The request body is empty, truncated or not valid JSON.",
+			"Send a single JSON object; details.offset points at the first invalid byte.",
+			"Malformed JSON at offset 12", map[string]interface{}{"offset": 12}),
+		validationCode(ErrUnknownField, "The request body has a field the endpoint does not accept.",
+			"Remove the field named by details.field, or check its spelling.",
+			"Field 'odds' is not allowed", map[string]interface{}{"field": "odds"}),
+		validationCode(ErrRequestTooLarge, "The request body exceeds the size limit of the endpoint.",
+			"Send a body smaller than details.max_bytes.",
+			"Request body must not exceed 1048576 bytes", map[string]interface{}{"max_bytes": 1048576}),
+		validationCode(ErrUnsupportedMediaType, "The request Content-Type is not JSON.",
+			"Send the body with Content-Type: application/json.",
+			"Content-Type must be application/json", map[string]interface{}{"content_type": "text/plain"}),
+
+		// Authentication Errors
+		Definition{Code: ErrInvalidToken, Type: AuthenticationError, Layer: ApplicationLayer,
+			Description: "The bearer token is missing, malformed or has an invalid signature.",
+			Remediation: "Sign in again to obtain a new token.",
+			Message:     "Token is invalid", Details: map[string]interface{}{"reason": "signature verification failed"}},
+		Definition{Code: ErrExpiredToken, Type: AuthenticationError, Layer: ApplicationLayer,
+			Description: "The bearer token has expired.",
+			Remediation: "Refresh the token and repeat the request.",
+			Message:     "Token has expired", Details: map[string]interface{}{"expired_at": "2024-06-15T11:00:00Z"}},
+		Definition{Code: ErrInvalidCredentials, Type: AuthenticationError, Layer: ApplicationLayer,
+			Description: "The request is not authenticated or the credentials are wrong.",
+			Remediation: "Check the email and password, or send an Authorization header.",
+			Message:     "Invalid credentials"},
+		Definition{Code: ErrTokenNotYetValid, Type: AuthenticationError, Layer: ApplicationLayer,
+			Description: "The bearer token is not valid yet.", Retryable: true,
+			Remediation: "Check the client clock; the request succeeds once the token's nbf time has passed.",
+			Message:     "Token is not valid yet", Details: map[string]interface{}{"not_before": "2024-06-15T13:00:00Z"}},
+		Definition{Code: ErrInvalidIssuer, Type: AuthenticationError, Layer: ApplicationLayer,
+			Description: "The bearer token was issued by an untrusted issuer.",
+			Remediation: "Obtain the token from this environment's identity provider.",
+			Message:     "Token issuer 'evil' is not trusted",
+			Details:     map[string]interface{}{"issuer": "evil", "expected_issuer": "betmates"}},
+		Definition{Code: ErrInvalidAudience, Type: AuthenticationError, Layer: ApplicationLayer,
+			Description: "The bearer token is not meant for this service.",
+			Remediation: "Request a token whose audience includes this service.",
+			Message:     "Token is not intended for 'wallet'",
+			Details:     map[string]interface{}{"audience": []string{"admin"}, "expected_audience": "wallet"}},
+
+		// Authorization Errors
+		Definition{Code: ErrInsufficientPermissions, Type: AuthorizationError, Layer: ApplicationLayer,
+			Description: "The caller lacks the permission the operation needs.",
+			Remediation: "Ask an administrator to grant details.permission.",
+			Message:     "Permission 'bets:settle' is required",
+			Details:     map[string]interface{}{"permission": "bets:settle", "subject": "user-42", "reason": "no role or rule grants the permission"}},
+		Definition{Code: ErrAccessDenied, Type: AuthorizationError, Layer: ApplicationLayer,
+			Description: "A policy rule explicitly denies the operation to the caller.",
+			Remediation: "The rule in details.rule applies to the account, such as a self-exclusion. Support can explain it; do not retry.",
+			Message:     "Access to 'wallet:withdraw' is denied",
+			Details: map[string]interface{}{"permission": "wallet:withdraw", "subject": "user-42",
+				"rule": "self-excluded", "reason": "denied by rule 'self-excluded'"}},
+
+		// Not Found Errors
+		Definition{Code: ErrUserNotFound, Type: NotFoundError, Layer: DomainLayer,
+			Description: "The user does not exist.",
+			Remediation: "Check the user ID; the account may have been closed.",
+			Message:     "User not found"},
+		Definition{Code: ErrResourceNotFound, Type: NotFoundError, Layer: DomainLayer,
+			Description: "The requested resource does not exist.",
+			Remediation: "Check the ID in the request path.",
+			Message:     "Resource not found"},
+
+		// Conflict Errors
+		Definition{Code: ErrUserAlreadyExists, Type: ConflictError, Layer: DomainLayer,
+			Description: "A user with the same identity already exists.",
+			Remediation: "Sign in to the existing account or recover its password.",
+			Message:     "User already exists"},
+		Definition{Code: ErrEmailAlreadyTaken, Type: ConflictError, Layer: DomainLayer,
+			Description: "The email address belongs to another account.",
+			Remediation: "Use another email address, or sign in to the account that owns it.",
+			Message:     "Email already taken"},
+		Definition{Code: ErrDuplicateIdempotencyKey, Type: ConflictError, Layer: DomainLayer,
+			Description: "A ledger operation with the same idempotency key was already recorded.",
+			Remediation: "The operation already happened. Fetch its result instead of repeating it.",
+			Message:     "Idempotency key 'deposit-1' was already used", Details: map[string]interface{}{"idempotency_key": "deposit-1"}},
+		Definition{Code: ErrAccountAlreadyExists, Type: ConflictError, Layer: DomainLayer,
+			Description: "A ledger account with the same ID already exists.",
+			Remediation: "Use the existing account.",
+			Message:     "Account 'wallet' already exists", Details: map[string]interface{}{"account": "wallet"}},
+		Definition{Code: ErrIdempotencyKeyReused, Type: ConflictError, Layer: DomainLayer,
+			Description: "The Idempotency-Key was already used for a request with a different body.",
+			Remediation: "Generate a new Idempotency-Key for each distinct request.",
+			Message:     "Idempotency key was already used for a different request",
+			Details:     map[string]interface{}{"idempotency_key": "key-1"}},
+		Definition{Code: ErrRequestInProgress, Type: ConflictError, Layer: DomainLayer,
+			Description: "A request with the same Idempotency-Key is still being processed.", Retryable: true,
+			Remediation: "Wait and retry with the same key to receive the original response.",
+			Message:     "A request with this idempotency key is still being processed",
+			Details:     map[string]interface{}{"idempotency_key": "key-1"}},
+
+		// Business Rule Errors
+		Definition{Code: ErrInvalidBusinessRule, Type: BusinessRuleError, Layer: DomainLayer,
+			Description: "The operation breaks a business rule.",
+			Remediation: "Read the message for the rule; change the bet slip or wait for the market to reopen.",
+			Message:     "Market is suspended"},
+		Definition{Code: ErrInvalidState, Type: BusinessRuleError, Layer: DomainLayer,
+			Description: "The resource is not in a state that allows the operation.",
+			Remediation: "Reload the resource; details.from is its current state.",
+			Message:     "Cannot move bet from 'settled' to 'cancelled'",
+			Details:     map[string]interface{}{"from": "settled", "to": "cancelled"}},
+		Definition{Code: ErrStakeOutOfRange, Type: BusinessRuleError, Layer: DomainLayer,
+			Description: "The stake is below the minimum or above the maximum of the market.",
+			Remediation: "Choose a stake between details.min and details.max.",
+			Message:     "Field 'stake' must be between 10.00 and 5000.00 MXN",
+			Details:     map[string]interface{}{"field": "stake", "min": "10.00", "max": "5000.00"}},
+		Definition{Code: ErrLimitExceeded, Type: BusinessRuleError, Layer: DomainLayer,
+			Description: "The operation would exceed a responsible-gambling limit.",
+			Remediation: "The customer's own limit applies until the period in details.period resets. Support cannot lift it early.",
+			Message:     "Daily deposit limit exceeded", Details: map[string]interface{}{"limit": "deposit", "period": "daily"}},
+		Definition{Code: ErrInvalidSettlement, Type: BusinessRuleError, Layer: DomainLayer,
+			Description: "The settlement result is not valid for the bet.",
+			Remediation: "Check the results feed for the selection before settling again.",
+			Message:     "Selection has no result"},
+		Definition{Code: ErrInsufficientFunds, Type: BusinessRuleError, Layer: DomainLayer,
+			Description: "The account balance does not cover the operation.",
+			Remediation: "Deposit funds or lower the amount.",
+			Message:     "Account 'wallet' has insufficient funds",
+			Details:     map[string]interface{}{"account": "wallet", "available": "5.00", "required": "10.00", "currency": "MXN"}},
+
+		// Rate Limit Errors
+		Definition{Code: ErrRateLimited, Type: RateLimitError, Layer: ApplicationLayer,
+			Description: "Too many requests. Retry after the number of seconds in Retry-After.", Retryable: true,
+			Remediation: "Wait for Retry-After seconds and slow down the client.",
+			Message:     "Rate limit exceeded, retry in 30 seconds",
+			Details:     map[string]interface{}{"limit": 100, "remaining": 0, "retry_after": 30, "reset": 30}},
+
+		// Infrastructure Errors
+		Definition{Code: ErrDatabaseConnection, Type: InfrastructureError, Layer: InfrastructureLayer,
+			Description: "The service lost its database connection.", Retryable: true,
+			Remediation: "Retry with backoff. If it persists, the service is degraded; check the status page.",
+			Message:     "Database connection lost"},
+		Definition{Code: ErrExternalService, Type: InfrastructureError, Layer: InfrastructureLayer,
+			Description: "A downstream service failed.", Retryable: true,
+			Remediation: "Retry with backoff. If it persists, a provider such as payments or odds is down.",
+			Message:     "External call failed"},
+		Definition{Code: ErrRepositoryOperation, Type: InfrastructureError, Layer: InfrastructureLayer,
+			Description: "The service could not read or write its data. The request was not applied.", Retryable: true,
+			Remediation: "Nothing was changed on the customer's account. Retry the request; if it keeps failing, report it with the request ID.",
+			Message:     "Repository operation failed"},
+		Definition{Code: ErrCircuitOpen, Type: InfrastructureError, Layer: InfrastructureLayer,
+			Description: "A dependency is failing and calls to it are paused.", Retryable: true,
+			Remediation: "Retry after Retry-After seconds.",
+			Message:     "Circuit 'odds-provider' is open",
+			Details:     map[string]interface{}{"circuit": "odds-provider", "state": "open", "retry_after": 20}},
+		Definition{Code: ErrTimeout, Type: InfrastructureError, Layer: InfrastructureLayer,
+			Description: "An operation did not finish in time.", Retryable: true,
+			Remediation: "Retry with an Idempotency-Key, since the operation may have completed.",
+			Message:     "Operation timed out"},
+		Definition{Code: ErrRequestCanceled, Type: InfrastructureError, Layer: InfrastructureLayer,
+			Description: "The client canceled the request before it finished.",
+			Remediation: "The client closed the connection; no action is needed on the server.",
+			Message:     "Operation was canceled"},
+
+		// Internal Errors
+		Definition{Code: ErrInternal, Type: InternalError, Layer: ApplicationLayer,
+			Description: "An unexpected error. It is logged with its cause.",
+			Remediation: "Report it with the request ID; retrying will usually fail the same way.",
+			Message:     "Internal error"},
+	)
+}
@@ -17,20 +17,41 @@ const (
 	BusinessRuleError   ErrorType = "business_rule"
 	InfrastructureError ErrorType = "infrastructure"
 	InternalError       ErrorType = "internal"
+	RateLimitError      ErrorType = "rate_limit"
 )
 
 // Error codes
 const (
 	// Validation Errors
-	ErrInvalidEmail    ErrorCode = "INVALID_EMAIL"
-	ErrInvalidPassword ErrorCode = "INVALID_PASSWORD"
-	ErrMissingRequired ErrorCode = "MISSING_REQUIRED"
-	ErrInvalidFormat   ErrorCode = "INVALID_FORMAT"
+	ErrInvalidEmail         ErrorCode = "INVALID_EMAIL"
+	ErrInvalidPassword      ErrorCode = "INVALID_PASSWORD"
+	ErrMissingRequired      ErrorCode = "MISSING_REQUIRED"
+	ErrInvalidFormat        ErrorCode = "INVALID_FORMAT"
+	ErrInvalidDate          ErrorCode = "INVALID_DATE"
+	ErrUnderage             ErrorCode = "UNDERAGE"
+	ErrInvalidUUID          ErrorCode = "INVALID_UUID"
+	ErrInvalidURL           ErrorCode = "INVALID_URL"
+	ErrInvalidPhone         ErrorCode = "INVALID_PHONE"
+	ErrInvalidCountry       ErrorCode = "INVALID_COUNTRY"
+	ErrInvalidCurrency      ErrorCode = "INVALID_CURRENCY"
+	ErrInvalidIP            ErrorCode = "INVALID_IP"
+	ErrInvalidCIDR          ErrorCode = "INVALID_CIDR"
+	ErrInvalidSlug          ErrorCode = "INVALID_SLUG"
+	ErrInvalidBase64        ErrorCode = "INVALID_BASE64"
+	ErrInvalidHex           ErrorCode = "INVALID_HEX"
+	ErrInvalidAmount        ErrorCode = "INVALID_AMOUNT"
+	ErrMalformedRequest     ErrorCode = "MALFORMED_REQUEST"
+	ErrUnknownField         ErrorCode = "UNKNOWN_FIELD"
+	ErrRequestTooLarge      ErrorCode = "REQUEST_TOO_LARGE"
+	ErrUnsupportedMediaType ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
 
 	// Authentication Errors
 	ErrInvalidToken       ErrorCode = "INVALID_TOKEN"
 	ErrExpiredToken       ErrorCode = "EXPIRED_TOKEN"
 	ErrInvalidCredentials ErrorCode = "INVALID_CREDENTIALS"
+	ErrTokenNotYetValid   ErrorCode = "TOKEN_NOT_YET_VALID"
+	ErrInvalidIssuer      ErrorCode = "INVALID_ISSUER"
+	ErrInvalidAudience    ErrorCode = "INVALID_AUDIENCE"
 
 	// Authorization Errors
 	ErrInsufficientPermissions ErrorCode = "INSUFFICIENT_PERMISSIONS"
@@ -41,15 +62,32 @@ const (
 	ErrResourceNotFound ErrorCode = "RESOURCE_NOT_FOUND"
 
 	// Conflict Errors
-	ErrUserAlreadyExists ErrorCode = "USER_ALREADY_EXISTS"
-	ErrEmailAlreadyTaken ErrorCode = "EMAIL_ALREADY_TAKEN"
+	ErrUserAlreadyExists       ErrorCode = "USER_ALREADY_EXISTS"
+	ErrEmailAlreadyTaken       ErrorCode = "EMAIL_ALREADY_TAKEN"
+	ErrDuplicateIdempotencyKey ErrorCode = "DUPLICATE_IDEMPOTENCY_KEY"
+	ErrAccountAlreadyExists    ErrorCode = "ACCOUNT_ALREADY_EXISTS"
+	ErrIdempotencyKeyReused    ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	ErrRequestInProgress       ErrorCode = "REQUEST_IN_PROGRESS"
 
 	// Business Rule Errors
 	ErrInvalidBusinessRule ErrorCode = "INVALID_BUSINESS_RULE"
 	ErrInvalidState        ErrorCode = "INVALID_STATE"
+	ErrStakeOutOfRange     ErrorCode = "STAKE_OUT_OF_RANGE"
+	ErrLimitExceeded       ErrorCode = "LIMIT_EXCEEDED"
+	ErrInvalidSettlement   ErrorCode = "INVALID_SETTLEMENT"
+	ErrInsufficientFunds   ErrorCode = "INSUFFICIENT_FUNDS"
+
+	// Rate Limit Errors
+	ErrRateLimited ErrorCode = "RATE_LIMITED"
 
 	// Infrastructure Errors
 	ErrDatabaseConnection  ErrorCode = "DATABASE_CONNECTION"
 	ErrExternalService     ErrorCode = "EXTERNAL_SERVICE"
 	ErrRepositoryOperation ErrorCode = "REPOSITORY_OPERATION"
+	ErrCircuitOpen         ErrorCode = "CIRCUIT_OPEN"
+	ErrTimeout             ErrorCode = "TIMEOUT"
+	ErrRequestCanceled     ErrorCode = "REQUEST_CANCELED"
+
+	// Internal Errors
+	ErrInternal ErrorCode = "INTERNAL"
 )
@@ -54,6 +54,13 @@ func NewAuthorizationError(code ErrorCode, message string, details ...map[string
 	return NewApplicationError(code, AuthorizationError, message, details...)
 }
 
+// NewRateLimitError reports a request rejected by a rate limiter. Details
+// should carry "retry_after" in seconds so that protocol handlers can tell
+// the client when to retry.
+func NewRateLimitError(code ErrorCode, message string, details ...map[string]interface{}) LayerError {
+	return NewApplicationError(code, RateLimitError, message, details...)
+}
+
 func NewNotFoundError(code ErrorCode, message string, details ...map[string]interface{}) LayerError {
 	return NewDomainError(code, NotFoundError, message, details...)
 }
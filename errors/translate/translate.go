@@ -0,0 +1,161 @@
+// Package translate converts errors from the standard library and drivers
+// into LayerErrors, keeping the original error as the cause.
+package translate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// Translator converts err, returning nil when it does not recognize it.
+type Translator func(err error) errors.LayerError
+
+// Chain tries its translators in order; the first non-nil result wins.
+type Chain []Translator
+
+// Default translates database, context, network, JSON and I/O errors.
+var Default = Chain{SQL, Network, Context, JSON, IO}
+
+// Translate converts err with the Default chain.
+func Translate(err error) errors.LayerError {
+	return Default.Translate(err)
+}
+
+// With returns a chain that tries ts before the translators of c, so that
+// services can override or extend the defaults.
+func (c Chain) With(ts ...Translator) Chain {
+	return append(append(Chain{}, ts...), c...)
+}
+
+// Translate converts err. A nil err returns nil, and an err that already is
+// or wraps a LayerError is returned as that LayerError. Unrecognized errors
+// become ErrInternal internal errors. The messages of translated errors never
+// include the cause, which may hold internal details; it is kept for
+// logging through Unwrap.
+func (c Chain) Translate(err error) errors.LayerError {
+	if err == nil {
+		return nil
+	}
+	var layerErr errors.LayerError
+	if stderrors.As(err, &layerErr) {
+		return layerErr
+	}
+	for _, t := range c {
+		if translated := t(err); translated != nil {
+			return errors.WithCause(translated, err)
+		}
+	}
+	return errors.WithCause(errors.NewApplicationError(errors.ErrInternal, errors.InternalError, "Internal error"), err)
+}
+
+// SQL translates sql.ErrNoRows to ErrResourceNotFound and lost connections
+// to ErrDatabaseConnection.
+func SQL(err error) errors.LayerError {
+	switch {
+	case stderrors.Is(err, sql.ErrNoRows):
+		return errors.NewNotFoundError(errors.ErrResourceNotFound, "Resource not found")
+	case stderrors.Is(err, sql.ErrConnDone), stderrors.Is(err, driver.ErrBadConn):
+		return errors.NewInfrastructureError(errors.ErrDatabaseConnection, "Database connection lost")
+	case stderrors.Is(err, sql.ErrTxDone):
+		return errors.NewInfrastructureError(errors.ErrRepositoryOperation, "Transaction already finished")
+	}
+	return nil
+}
+
+// Context translates context.DeadlineExceeded to ErrTimeout and
+// context.Canceled to ErrRequestCanceled.
+func Context(err error) errors.LayerError {
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return errors.NewInfrastructureError(errors.ErrTimeout, "Operation timed out")
+	case stderrors.Is(err, context.Canceled):
+		return errors.NewInfrastructureError(errors.ErrRequestCanceled, "Operation was canceled")
+	}
+	return nil
+}
+
+// Network translates *url.Error and *net.OpError to ErrExternalService, or
+// ErrTimeout when the error is a timeout and ErrRequestCanceled when the
+// call's context was canceled. Details carry the operation and the URL or
+// address.
+func Network(err error) errors.LayerError {
+	details := map[string]any{}
+	var urlErr *url.Error
+	var opErr *net.OpError
+	switch {
+	case stderrors.As(err, &urlErr):
+		details["op"] = urlErr.Op
+		details["url"] = redact(urlErr.URL)
+	case stderrors.As(err, &opErr):
+		details["op"] = opErr.Op
+		if opErr.Addr != nil {
+			details["addr"] = opErr.Addr.String()
+		}
+	default:
+		return nil
+	}
+	if stderrors.Is(err, context.Canceled) {
+		return errors.NewInfrastructureError(errors.ErrRequestCanceled, "External call was canceled", details)
+	}
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && netErr.Timeout() {
+		return errors.NewInfrastructureError(errors.ErrTimeout, "External call timed out", details)
+	}
+	return errors.NewInfrastructureError(errors.ErrExternalService, "External call failed", details)
+}
+
+// JSON translates decoding errors. Syntax errors become ErrMalformedRequest
+// with the byte "offset"; type mismatches become ErrInvalidFormat with the
+// "field" path, "offset", "expected" type and the JSON "value" kind.
+func JSON(err error) errors.LayerError {
+	var syntaxErr *json.SyntaxError
+	if stderrors.As(err, &syntaxErr) {
+		return errors.NewValidationError(errors.ErrMalformedRequest,
+			fmt.Sprintf("Malformed JSON at offset %d", syntaxErr.Offset),
+			map[string]any{"offset": syntaxErr.Offset, "reason": syntaxErr.Error()})
+	}
+	var typeErr *json.UnmarshalTypeError
+	if stderrors.As(err, &typeErr) {
+		return errors.NewValidationError(errors.ErrInvalidFormat,
+			fmt.Sprintf("Field '%s' must be %s", typeErr.Field, typeErr.Type),
+			map[string]any{
+				"field":    typeErr.Field,
+				"offset":   typeErr.Offset,
+				"expected": typeErr.Type.String(),
+				"value":    typeErr.Value,
+			})
+	}
+	return nil
+}
+
+// IO translates a truncated input, io.ErrUnexpectedEOF, to
+// ErrMalformedRequest. A bare io.EOF is left to the caller, since it usually
+// marks the normal end of a stream. Use a chain without IO where truncation
+// means a broken upstream connection rather than a bad request body.
+func IO(err error) errors.LayerError {
+	if stderrors.Is(err, io.ErrUnexpectedEOF) {
+		return errors.NewValidationError(errors.ErrMalformedRequest, "Input ended unexpectedly",
+			map[string]any{"reason": err.Error()})
+	}
+	return nil
+}
+
+// redact drops credentials and the query string, which may hold secrets.
+func redact(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
@@ -44,6 +44,27 @@ func (e *baseError) Details() map[string]interface{} {
 	return e.details
 }
 
+// causedError attaches the underlying error to a LayerError without changing
+// its message, so that callers can still use errors.Is and errors.As on the
+// original cause.
+type causedError struct {
+	LayerError
+	cause error
+}
+
+func (e *causedError) Unwrap() error {
+	return e.cause
+}
+
+// WithCause returns err with cause attached. The result reports the same
+// layer, code, type, message and details as err, and Unwrap returns cause.
+func WithCause(err LayerError, cause error) LayerError {
+	if err == nil || cause == nil {
+		return err
+	}
+	return &causedError{LayerError: err, cause: cause}
+}
+
 func (l LayerType) String() string {
 	return string(l)
 }
@@ -0,0 +1,136 @@
+package fsm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// Definition lists the states each state may move to. States that appear only
+// as targets are terminal.
+type Definition[S ~string] map[S][]S
+
+// Guard vetoes a transition by returning an error, e.g. a settlement that is
+// missing results.
+type Guard[S ~string] func(ctx context.Context, from, to S) errors.LayerError
+
+// Event is emitted after every successful transition.
+type Event[S ~string] struct {
+	From S
+	To   S
+}
+
+type Listener[S ~string] func(ctx context.Context, event Event[S])
+
+// Machine validates transitions against a Definition. It holds no current
+// state, so one machine serves every entity of a kind. Register guards and
+// listeners before use; after that a Machine is safe for concurrent use.
+type Machine[S ~string] struct {
+	definition Definition[S]
+	guards     map[Event[S]][]Guard[S]
+	listeners  []Listener[S]
+}
+
+func New[S ~string](definition Definition[S]) *Machine[S] {
+	return &Machine[S]{definition: definition, guards: make(map[Event[S]][]Guard[S])}
+}
+
+// Guard registers a guard for the transition from -> to.
+func (m *Machine[S]) Guard(from, to S, guard Guard[S]) *Machine[S] {
+	key := Event[S]{From: from, To: to}
+	m.guards[key] = append(m.guards[key], guard)
+	return m
+}
+
+// OnTransition registers a listener called after each successful transition.
+func (m *Machine[S]) OnTransition(listener Listener[S]) *Machine[S] {
+	m.listeners = append(m.listeners, listener)
+	return m
+}
+
+// Can reports whether the definition allows from -> to. Guards are not run.
+func (m *Machine[S]) Can(from, to S) bool {
+	for _, target := range m.definition[from] {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed returns the targets reachable from a state.
+func (m *Machine[S]) Allowed(from S) []S {
+	return append([]S(nil), m.definition[from]...)
+}
+
+func (m *Machine[S]) IsTerminal(state S) bool {
+	return len(m.definition[state]) == 0
+}
+
+// Transition checks that from -> to is allowed, runs its guards and notifies
+// the listeners. Illegal transitions return a BusinessRuleError with code
+// ErrInvalidState and details from, to and allowed; guard errors are returned
+// unchanged.
+func (m *Machine[S]) Transition(ctx context.Context, from, to S) errors.LayerError {
+	if !m.Can(from, to) {
+		allowed := make([]string, 0, len(m.definition[from]))
+		for _, target := range m.definition[from] {
+			allowed = append(allowed, string(target))
+		}
+		return errors.NewBusinessRuleError(errors.ErrInvalidState,
+			fmt.Sprintf("Cannot transition from '%s' to '%s'", from, to),
+			map[string]any{"from": string(from), "to": string(to), "allowed": allowed})
+	}
+	event := Event[S]{From: from, To: to}
+	for _, guard := range m.guards[event] {
+		if err := guard(ctx, from, to); err != nil {
+			return err
+		}
+	}
+	for _, listener := range m.listeners {
+		listener(ctx, event)
+	}
+	return nil
+}
+
+// DOT renders the definition as a Graphviz digraph named name. Terminal
+// states are drawn with a double circle.
+func (m *Machine[S]) DOT(name string) string {
+	states := make(map[S]bool)
+	for from, targets := range m.definition {
+		states[from] = true
+		for _, to := range targets {
+			states[to] = true
+		}
+	}
+	sorted := make([]string, 0, len(states))
+	for s := range states {
+		sorted = append(sorted, string(s))
+	}
+	sort.Strings(sorted)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", name)
+	for _, s := range sorted {
+		shape := "circle"
+		if m.IsTerminal(S(s)) {
+			shape = "doublecircle"
+		}
+		fmt.Fprintf(&b, "  %q [shape=%s];\n", s, shape)
+	}
+	for _, from := range sorted {
+		targets := make([]string, 0, len(m.definition[S(from)]))
+		for _, to := range m.definition[S(from)] {
+			targets = append(targets, string(to))
+		}
+		sort.Strings(targets)
+		for _, to := range targets {
+			fmt.Fprintf(&b, "  %q -> %q;\n", from, to)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
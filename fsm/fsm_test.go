@@ -0,0 +1,99 @@
+package fsm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+type betState string
+
+const (
+	pending   betState = "pending"
+	accepted  betState = "accepted"
+	rejected  betState = "rejected"
+	settled   betState = "settled"
+	void      betState = "void"
+	cashedOut betState = "cashed_out"
+)
+
+var betLifecycle = Definition[betState]{
+	pending:  {accepted, rejected},
+	accepted: {settled, void, cashedOut},
+}
+
+func TestMachine_Transition(t *testing.T) {
+	m := New(betLifecycle)
+
+	tests := []struct {
+		name        string
+		from, to    betState
+		wantErr     bool
+		wantAllowed []string
+	}{
+		{"Pending to accepted", pending, accepted, false, nil},
+		{"Accepted to settled", accepted, settled, false, nil},
+		{"Accepted to cashed out", accepted, cashedOut, false, nil},
+		{"Pending to settled", pending, settled, true, []string{"accepted", "rejected"}},
+		{"Settled is terminal", settled, void, true, []string{}},
+		{"Unknown state", "lost", accepted, true, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.Transition(context.Background(), tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Transition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if err.Code() != errors.ErrInvalidState || err.Type() != errors.BusinessRuleError {
+				t.Errorf("error = %v/%v, want %v/%v", err.Code(), err.Type(), errors.ErrInvalidState, errors.BusinessRuleError)
+			}
+			details := err.Details()
+			if details["from"] != string(tt.from) || details["to"] != string(tt.to) {
+				t.Errorf("Details() = %v, want from %s to %s", details, tt.from, tt.to)
+			}
+			if !reflect.DeepEqual(details["allowed"], tt.wantAllowed) {
+				t.Errorf("Details[allowed] = %v, want %v", details["allowed"], tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestMachine_GuardsAndEvents(t *testing.T) {
+	var events []Event[betState]
+	veto := errors.NewBusinessRuleError(errors.ErrInvalidBusinessRule, "Cash out is suspended")
+	m := New(betLifecycle).
+		Guard(accepted, cashedOut, func(context.Context, betState, betState) errors.LayerError { return veto }).
+		OnTransition(func(_ context.Context, e Event[betState]) { events = append(events, e) })
+
+	if err := m.Transition(context.Background(), accepted, cashedOut); err != veto {
+		t.Errorf("Transition() error = %v, want guard error", err)
+	}
+	if err := m.Transition(context.Background(), accepted, settled); err != nil {
+		t.Fatalf("Transition() unexpected error: %v", err)
+	}
+	want := []Event[betState]{{From: accepted, To: settled}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestMachine_DOT(t *testing.T) {
+	m := New(Definition[betState]{pending: {rejected, accepted}})
+	want := `digraph "bet" {
+  "accepted" [shape=doublecircle];
+  "pending" [shape=circle];
+  "rejected" [shape=doublecircle];
+  "pending" -> "accepted";
+  "pending" -> "rejected";
+}
+`
+	if got := m.DOT("bet"); got != want {
+		t.Errorf("DOT() =\n%s\nwant\n%s", got, want)
+	}
+}
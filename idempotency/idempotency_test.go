@@ -0,0 +1,235 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+func newTestServer(t *testing.T, store Store, handler http.HandlerFunc) http.Handler {
+	t.Helper()
+	return Middleware(Options{Store: store})(handler)
+}
+
+func post(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/bets", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Response body is not JSON: %v", err)
+	}
+	code, _ := body["code"].(string)
+	return code
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		first      string
+		second     string
+		status     int
+		wantStatus int
+		wantCode   errors.ErrorCode
+		wantCalls  int32
+	}{
+		{"Replays same request", `{"stake":100}`, `{"stake":100}`, http.StatusCreated, http.StatusCreated, "", 1},
+		{"Rejects different body", `{"stake":100}`, `{"stake":200}`, http.StatusCreated, http.StatusConflict, errors.ErrIdempotencyKeyReused, 1},
+		{"Replays client errors", `{}`, `{}`, http.StatusBadRequest, http.StatusBadRequest, "", 1},
+		{"Retries server errors", `{"stake":100}`, `{"stake":100}`, http.StatusBadGateway, http.StatusBadGateway, "", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			h := newTestServer(t, NewMemoryStore(clock.System()), func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.Header().Set("Location", "/bets/1")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"id":1}`))
+			})
+
+			post(h, "key-1", tt.first)
+			rec := post(h, "key-1", tt.second)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Handler calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantCode != "" {
+				if got := errorCode(t, rec); got != string(tt.wantCode) {
+					t.Errorf("Code = %v, want %v", got, tt.wantCode)
+				}
+				return
+			}
+			if tt.wantCalls == 1 {
+				if rec.Header().Get(ReplayedHeader) != "true" {
+					t.Errorf("Header %s = %q, want true", ReplayedHeader, rec.Header().Get(ReplayedHeader))
+				}
+				if rec.Header().Get("Location") != "/bets/1" || rec.Body.String() != `{"id":1}` {
+					t.Errorf("Replayed response = %v %q, want original", rec.Header(), rec.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestMiddleware_WithoutKey(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, r *http.Request) { atomic.AddInt32(&calls, 1) }
+
+	h := newTestServer(t, NewMemoryStore(clock.System()), handler)
+	post(h, "", `{}`)
+	post(h, "", `{}`)
+	if calls != 2 {
+		t.Errorf("Handler calls = %d, want 2", calls)
+	}
+
+	required := Middleware(Options{Store: NewMemoryStore(clock.System()), Required: true})(http.HandlerFunc(handler))
+	rec := post(required, "", `{}`)
+	if rec.Code != http.StatusBadRequest || errorCode(t, rec) != string(errors.ErrMissingRequired) {
+		t.Errorf("Response = %d %s, want 400 %s", rec.Code, rec.Body.String(), errors.ErrMissingRequired)
+	}
+}
+
+func TestMiddleware_RequestInProgress(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	h := newTestServer(t, NewMemoryStore(clock.System()), func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(h, "key-1", `{}`) }()
+	<-entered
+
+	rec := post(h, "key-1", `{}`)
+	if rec.Code != http.StatusConflict || errorCode(t, rec) != string(errors.ErrRequestInProgress) {
+		t.Errorf("Concurrent response = %d %s, want 409 %s", rec.Code, rec.Body.String(), errors.ErrRequestInProgress)
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("Original status = %d, want %d", first.Code, http.StatusCreated)
+	}
+}
+
+func TestMiddleware_AbandonsOnPanic(t *testing.T) {
+	store := NewMemoryStore(clock.System())
+	h := newTestServer(t, store, func(w http.ResponseWriter, r *http.Request) { panic("boom") })
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Expected panic to propagate")
+			}
+		}()
+		post(h, "key-1", `{}`)
+	}()
+
+	if _, started, _ := store.Begin(t.Context(), "key-1", "fp", time.Minute); !started {
+		t.Error("Expected key to be released after panic")
+	}
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))
+	store := NewMemoryStore(fake)
+	ctx := t.Context()
+
+	if _, started, _ := store.Begin(ctx, "k", "a", time.Hour); !started {
+		t.Fatal("Expected first Begin to start")
+	}
+	if _, started, _ := store.Begin(ctx, "k", "a", time.Hour); started {
+		t.Error("Expected second Begin to return the existing record")
+	}
+	fake.Advance(time.Hour)
+	if _, started, _ := store.Begin(ctx, "k", "b", time.Hour); !started {
+		t.Error("Expected Begin to start again after expiry")
+	}
+}
+
+func TestMemoryStore_SweepsExpired(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC))
+	store := NewMemoryStore(fake)
+	ctx := t.Context()
+
+	for _, key := range []string{"a", "b", "c"} {
+		store.Begin(ctx, key, "fp", time.Second)
+	}
+	fake.Advance(2 * sweepInterval)
+	store.Begin(ctx, "d", "fp", time.Hour)
+	if got := len(store.records); got != 1 {
+		t.Errorf("Records after sweep = %d, want 1", got)
+	}
+}
+
+func TestMiddleware_Request(t *testing.T) {
+	var calls int32
+	h := Middleware(Options{MaxBytes: 16})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	send := func(target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-1")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send("/bets", strings.Repeat("x", 17)); rec.Code != http.StatusRequestEntityTooLarge || errorCode(t, rec) != string(errors.ErrRequestTooLarge) {
+		t.Errorf("Oversized response = %d %s, want 413 %s", rec.Code, rec.Body.String(), errors.ErrRequestTooLarge)
+	}
+	if rec := send("/bets?market=1", `{}`); rec.Code != http.StatusCreated {
+		t.Fatalf("First response = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if rec := send("/bets?market=2", `{}`); rec.Code != http.StatusConflict || errorCode(t, rec) != string(errors.ErrIdempotencyKeyReused) {
+		t.Errorf("Different query response = %d %s, want 409 %s", rec.Code, rec.Body.String(), errors.ErrIdempotencyKeyReused)
+	}
+	if calls != 1 {
+		t.Errorf("Handler calls = %d, want 1", calls)
+	}
+}
+
+// TestCatalogExamples keeps the catalog examples, which feed the OpenAPI and
+// error docs, in step with what the middleware returns.
+func TestCatalogExamples(t *testing.T) {
+	for _, existing := range []Record{{Fingerprint: "other"}, {Fingerprint: "same"}} {
+		err := checkExisting(existing, "same", "key-1")
+		if err == nil {
+			t.Fatal("checkExisting() expected an error, got nil")
+		}
+		def, ok := errors.Lookup(err.Code())
+		if !ok {
+			t.Fatalf("%s is not in the catalog", err.Code())
+		}
+		want := def.Example()
+		if err.Error() != want.Error() || err.Type() != want.Type() || err.Layer() != want.Layer() {
+			t.Errorf("%s = %q (%s, %s), catalog example = %q (%s, %s)", err.Code(),
+				err.Error(), err.Type(), err.Layer(), want.Error(), want.Type(), want.Layer())
+		}
+		if len(err.Details()) != len(want.Details()) || want.Details()["idempotency_key"] != err.Details()["idempotency_key"] {
+			t.Errorf("%s details = %v, catalog example = %v", err.Code(), err.Details(), want.Details())
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/protocols"
+)
+
+// ReplayedHeader is set on responses served from the store.
+const ReplayedHeader = "Idempotent-Replayed"
+
+// DefaultMaxBytes is the body limit used when Options.MaxBytes is zero.
+const DefaultMaxBytes = 1 << 20
+
+type Options struct {
+	// Store keeps the records. Defaults to a MemoryStore on the system
+	// clock, which only deduplicates within one process.
+	Store Store
+	// Handler renders conflicts and store failures. Defaults to
+	// protocols.NewDefaultHTTPErrorHandler().
+	Handler protocols.HTTPErrorHandler
+	// Header carrying the key. Defaults to "Idempotency-Key".
+	Header string
+	// TTL of stored responses. Defaults to 24 hours.
+	TTL time.Duration
+	// Methods the middleware applies to. Defaults to POST and PATCH.
+	Methods []string
+	// Required rejects requests without a key with ErrMissingRequired.
+	Required bool
+	// MaxBytes limits the body read to fingerprint a request; larger bodies
+	// fail with ErrRequestTooLarge (413). Defaults to DefaultMaxBytes.
+	MaxBytes int64
+	// Scope namespaces keys, typically by authenticated user, so two clients
+	// cannot collide. Defaults to no scoping.
+	Scope func(r *http.Request) string
+}
+
+// Middleware makes retries of the same request safe. The first response for
+// a key is stored and replayed on retries with the same body; a retry with a
+// different body fails with ErrIdempotencyKeyReused and a duplicate that
+// arrives while the original is still running fails with
+// ErrRequestInProgress, both 409. Responses with a 5xx status are not
+// stored, so the client can retry them.
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore(clock.System())
+	}
+	if opts.Handler == nil {
+		opts.Handler = protocols.NewDefaultHTTPErrorHandler()
+	}
+	if opts.Header == "" {
+		opts.Header = "Idempotency-Key"
+	}
+	if opts.TTL == 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	if len(opts.Methods) == 0 {
+		opts.Methods = []string{http.MethodPost, http.MethodPatch}
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultMaxBytes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !contains(opts.Methods, r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := r.Header.Get(opts.Header)
+			if key == "" {
+				if opts.Required {
+					protocols.WriteHTTPError(w, opts.Handler, errors.NewValidationError(errors.ErrMissingRequired,
+						fmt.Sprintf("Header '%s' is required", opts.Header),
+						map[string]any{"field": opts.Header}))
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			if opts.Scope != nil {
+				key = opts.Scope(r) + ":" + key
+			}
+
+			body, readErr := io.ReadAll(http.MaxBytesReader(w, r.Body, opts.MaxBytes))
+			var tooLarge *http.MaxBytesError
+			if stderrors.As(readErr, &tooLarge) {
+				protocols.WriteHTTPError(w, opts.Handler, errors.NewValidationError(errors.ErrRequestTooLarge,
+					fmt.Sprintf("Request body must not exceed %d bytes", opts.MaxBytes),
+					map[string]any{"max_bytes": opts.MaxBytes}))
+				return
+			}
+			if readErr != nil {
+				protocols.WriteHTTPError(w, opts.Handler, errors.NewValidationError(errors.ErrInvalidFormat,
+					"Request body could not be read", map[string]any{"reason": readErr.Error()}))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			fingerprint := fingerprintOf(r, body)
+
+			existing, started, err := opts.Store.Begin(r.Context(), key, fingerprint, opts.TTL)
+			if err != nil {
+				protocols.WriteHTTPError(w, opts.Handler, err)
+				return
+			}
+			if !started {
+				if err := checkExisting(existing, fingerprint, r.Header.Get(opts.Header)); err != nil {
+					protocols.WriteHTTPError(w, opts.Handler, err)
+					return
+				}
+				replay(w, existing)
+				return
+			}
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			defer func() {
+				if !completed {
+					_ = opts.Store.Abandon(r.Context(), key)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+			if rec.status >= http.StatusInternalServerError {
+				return
+			}
+			completed = opts.Store.Complete(r.Context(), key, Record{
+				Fingerprint: fingerprint,
+				Status:      rec.status,
+				Header:      w.Header().Clone(),
+				Body:        rec.body.Bytes(),
+			}) == nil
+		})
+	}
+}
+
+func checkExisting(existing Record, fingerprint, key string) errors.LayerError {
+	if existing.Fingerprint != fingerprint {
+		return errors.NewConflictError(errors.ErrIdempotencyKeyReused,
+			"Idempotency key was already used for a different request",
+			map[string]any{"idempotency_key": key})
+	}
+	if !existing.Completed {
+		return errors.NewConflictError(errors.ErrRequestInProgress,
+			"A request with this idempotency key is still being processed",
+			map[string]any{"idempotency_key": key})
+	}
+	return nil
+}
+
+func replay(w http.ResponseWriter, record Record) {
+	for name, values := range record.Header {
+		w.Header()[name] = append([]string(nil), values...)
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(record.Status)
+	_, _ = w.Write(record.Body)
+}
+
+// fingerprintOf hashes the method, path, query and body so that a key reused
+// for a different operation is detected.
+func fingerprintOf(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s?%s\n", r.Method, r.URL.Path, r.URL.RawQuery)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func contains(values []string, v string) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
+
+// recorder passes the response through while keeping a copy of the status
+// and body.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
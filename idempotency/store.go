@@ -0,0 +1,93 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// Record is what a store keeps per key: the request fingerprint and, once the
+// original request finished, its response.
+type Record struct {
+	Fingerprint string
+	Completed   bool
+	Status      int
+	Header      http.Header
+	Body        []byte
+}
+
+// Store keeps idempotency records. Begin must be atomic: for a given key
+// exactly one caller gets started == true until the key is completed,
+// abandoned or expired.
+type Store interface {
+	// Begin reserves key for a new request, or returns the existing record.
+	Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (existing Record, started bool, err errors.LayerError)
+	// Complete stores the response of a request started with Begin.
+	Complete(ctx context.Context, key string, record Record) errors.LayerError
+	// Abandon releases a key whose request failed, so that it can be retried.
+	Abandon(ctx context.Context, key string) errors.LayerError
+}
+
+// sweepInterval is how often Begin drops expired records from a MemoryStore.
+const sweepInterval = time.Minute
+
+// MemoryStore is an in-process Store with per-key expiry. Expired records are
+// swept by Begin at most once per minute. It is safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	records map[string]memoryRecord
+	sweepAt time.Time
+}
+
+type memoryRecord struct {
+	Record
+	expiresAt time.Time
+}
+
+func NewMemoryStore(c clock.Clock) *MemoryStore {
+	return &MemoryStore{clock: c, records: make(map[string]memoryRecord)}
+}
+
+func (s *MemoryStore) Begin(_ context.Context, key, fingerprint string, ttl time.Duration) (Record, bool, errors.LayerError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	if !now.Before(s.sweepAt) {
+		for k, r := range s.records {
+			if !now.Before(r.expiresAt) {
+				delete(s.records, k)
+			}
+		}
+		s.sweepAt = now.Add(sweepInterval)
+	}
+	if r, ok := s.records[key]; ok && now.Before(r.expiresAt) {
+		return r.Record, false, nil
+	}
+	s.records[key] = memoryRecord{Record: Record{Fingerprint: fingerprint}, expiresAt: now.Add(ttl)}
+	return Record{}, true, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key string, record Record) errors.LayerError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[key]
+	if !ok {
+		return nil
+	}
+	r.Record = record
+	r.Record.Completed = true
+	s.records[key] = r
+	return nil
+}
+
+func (s *MemoryStore) Abandon(_ context.Context, key string) errors.LayerError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
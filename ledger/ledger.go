@@ -0,0 +1,309 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+)
+
+type AccountID string
+
+// Account is a wallet or an internal book. Accounts that may not go below
+// zero, such as player wallets, leave AllowNegative false; house and
+// settlement accounts usually set it.
+type Account struct {
+	ID            AccountID
+	Currency      string
+	AllowNegative bool
+}
+
+// Posting moves Amount into Account; negative amounts are debits.
+type Posting struct {
+	Account AccountID
+	Amount  money.Amount
+}
+
+// Transaction is a balanced set of postings identified by its idempotency
+// key. For every currency the postings sum to zero.
+type Transaction struct {
+	Key       string
+	Postings  []Posting
+	Metadata  map[string]string
+	CreatedAt time.Time
+}
+
+// Hold reserves funds of an account so they are not available to other
+// debits until the hold is released or captured.
+type Hold struct {
+	Key       string
+	Account   AccountID
+	Amount    money.Amount
+	CreatedAt time.Time
+}
+
+type Balance struct {
+	Total     money.Amount
+	Held      money.Amount
+	Available money.Amount
+}
+
+type Ledger struct {
+	store Store
+	clock clock.Clock
+}
+
+func New(store Store, c clock.Clock) *Ledger {
+	return &Ledger{store: store, clock: c}
+}
+
+func (l *Ledger) OpenAccount(ctx context.Context, account Account) errors.LayerError {
+	if _, ok := money.Exponent(account.Currency); !ok {
+		return errors.NewValidationError(errors.ErrInvalidCurrency,
+			fmt.Sprintf("Currency '%s' is not supported for accounts", account.Currency),
+			map[string]any{"account": string(account.ID), "currency": account.Currency})
+	}
+	return l.store.Update(ctx, func(tx Tx) errors.LayerError {
+		if _, ok := tx.Account(account.ID); ok {
+			return errors.NewConflictError(errors.ErrAccountAlreadyExists,
+				fmt.Sprintf("Account '%s' already exists", account.ID),
+				map[string]any{"account": string(account.ID)})
+		}
+		return tx.PutAccount(account)
+	})
+}
+
+// Post records a balanced transaction. A key that was already used returns a
+// ConflictError with code ErrDuplicateIdempotencyKey; a debit beyond the
+// available balance of a non-negative account returns a BusinessRuleError
+// with code ErrInsufficientFunds.
+func (l *Ledger) Post(ctx context.Context, t Transaction) (Transaction, errors.LayerError) {
+	if err := validateTransaction(t); err != nil {
+		return Transaction{}, err
+	}
+	t.CreatedAt = l.clock.Now()
+	err := l.store.Update(ctx, func(tx Tx) errors.LayerError {
+		if err := checkKey(tx, t.Key); err != nil {
+			return err
+		}
+		if err := checkFunds(tx, t.Postings, nil); err != nil {
+			return err
+		}
+		return tx.PutTransaction(t)
+	})
+	if err != nil {
+		return Transaction{}, err
+	}
+	return t, nil
+}
+
+// Hold reserves amount on the account under the idempotency key.
+func (l *Ledger) Hold(ctx context.Context, key string, account AccountID, amount money.Amount) (Hold, errors.LayerError) {
+	if key == "" {
+		return Hold{}, missingKey()
+	}
+	if !amount.IsPositive() {
+		return Hold{}, errors.NewValidationError(errors.ErrInvalidAmount, "Hold amount must be positive",
+			map[string]any{"account": string(account), "amount": amount.String()})
+	}
+	h := Hold{Key: key, Account: account, Amount: amount, CreatedAt: l.clock.Now()}
+	err := l.store.Update(ctx, func(tx Tx) errors.LayerError {
+		if err := checkKey(tx, key); err != nil {
+			return err
+		}
+		if err := checkFunds(tx, []Posting{{Account: account, Amount: negate(amount)}}, nil); err != nil {
+			return err
+		}
+		return tx.PutHold(h)
+	})
+	if err != nil {
+		return Hold{}, err
+	}
+	return h, nil
+}
+
+// Release cancels a hold, making its funds available again.
+func (l *Ledger) Release(ctx context.Context, holdKey string) errors.LayerError {
+	return l.store.Update(ctx, func(tx Tx) errors.LayerError {
+		if _, ok := tx.Hold(holdKey); !ok {
+			return holdNotFound(holdKey)
+		}
+		return tx.DeleteHold(holdKey)
+	})
+}
+
+// Capture atomically releases a hold and posts t, so the held funds count as
+// available to the transaction. The typical use is taking a reserved stake
+// once a bet is accepted.
+func (l *Ledger) Capture(ctx context.Context, holdKey string, t Transaction) (Transaction, errors.LayerError) {
+	if err := validateTransaction(t); err != nil {
+		return Transaction{}, err
+	}
+	t.CreatedAt = l.clock.Now()
+	err := l.store.Update(ctx, func(tx Tx) errors.LayerError {
+		h, ok := tx.Hold(holdKey)
+		if !ok {
+			return holdNotFound(holdKey)
+		}
+		if err := checkKey(tx, t.Key, holdKey); err != nil {
+			return err
+		}
+		if err := checkFunds(tx, t.Postings, &h); err != nil {
+			return err
+		}
+		if err := tx.DeleteHold(holdKey); err != nil {
+			return err
+		}
+		return tx.PutTransaction(t)
+	})
+	if err != nil {
+		return Transaction{}, err
+	}
+	return t, nil
+}
+
+func (l *Ledger) Balance(ctx context.Context, id AccountID) (Balance, errors.LayerError) {
+	var b Balance
+	err := l.store.View(ctx, func(tx Tx) errors.LayerError {
+		account, ok := tx.Account(id)
+		if !ok {
+			return accountNotFound(id)
+		}
+		total, held := tx.Balance(id), tx.Held(id)
+		b = Balance{
+			Total:     amountOf(total, account.Currency),
+			Held:      amountOf(held, account.Currency),
+			Available: amountOf(total-held, account.Currency),
+		}
+		return nil
+	})
+	return b, err
+}
+
+// Transaction returns a posted transaction by idempotency key.
+func (l *Ledger) Transaction(ctx context.Context, key string) (Transaction, errors.LayerError) {
+	var t Transaction
+	err := l.store.View(ctx, func(tx Tx) errors.LayerError {
+		found, ok := tx.Transaction(key)
+		if !ok {
+			return errors.NewNotFoundError(errors.ErrResourceNotFound,
+				fmt.Sprintf("Transaction '%s' not found", key),
+				map[string]any{"idempotency_key": key})
+		}
+		t = found
+		return nil
+	})
+	return t, err
+}
+
+func validateTransaction(t Transaction) errors.LayerError {
+	if t.Key == "" {
+		return missingKey()
+	}
+	if len(t.Postings) < 2 {
+		return unbalanced(t.Key, "a transaction needs at least two postings")
+	}
+	sums := make(map[string]int64)
+	for _, p := range t.Postings {
+		if p.Amount.IsZero() {
+			return unbalanced(t.Key, fmt.Sprintf("posting to '%s' has no amount", p.Account))
+		}
+		sums[p.Amount.Currency()] += p.Amount.Minor()
+	}
+	for currency, sum := range sums {
+		if sum != 0 {
+			return unbalanced(t.Key, fmt.Sprintf("%s postings do not sum to zero", currency))
+		}
+	}
+	return nil
+}
+
+// checkFunds verifies the accounts and currencies of the postings and that no
+// non-negative account ends below zero available. When capturing, the
+// captured hold counts as available.
+func checkFunds(tx Tx, postings []Posting, capturing *Hold) errors.LayerError {
+	deltas := make(map[AccountID]int64)
+	var order []AccountID
+	for _, p := range postings {
+		account, ok := tx.Account(p.Account)
+		if !ok {
+			return accountNotFound(p.Account)
+		}
+		if p.Amount.Currency() != account.Currency {
+			return errors.NewValidationError(errors.ErrInvalidCurrency,
+				fmt.Sprintf("Account '%s' holds %s, not %s", account.ID, account.Currency, p.Amount.Currency()),
+				map[string]any{"account": string(account.ID), "currency": p.Amount.Currency(), "expected_currency": account.Currency})
+		}
+		if _, seen := deltas[p.Account]; !seen {
+			order = append(order, p.Account)
+		}
+		deltas[p.Account] += p.Amount.Minor()
+	}
+	for _, id := range order {
+		account, _ := tx.Account(id)
+		if account.AllowNegative || deltas[id] >= 0 {
+			continue
+		}
+		available := tx.Balance(id) - tx.Held(id)
+		if capturing != nil && capturing.Account == id {
+			available += capturing.Amount.Minor()
+		}
+		if available+deltas[id] < 0 {
+			return errors.NewBusinessRuleError(errors.ErrInsufficientFunds,
+				fmt.Sprintf("Account '%s' has insufficient funds", id),
+				map[string]any{
+					"account":   string(id),
+					"available": amountOf(available, account.Currency).String(),
+					"required":  amountOf(-deltas[id], account.Currency).String(),
+					"currency":  account.Currency,
+				})
+		}
+	}
+	return nil
+}
+
+// checkKey rejects keys already used by a transaction or a hold. The hold
+// being captured may share its key with the capturing transaction.
+func checkKey(tx Tx, key string, capturing ...string) errors.LayerError {
+	_, posted := tx.Transaction(key)
+	_, held := tx.Hold(key)
+	if held && len(capturing) > 0 && capturing[0] == key {
+		held = false
+	}
+	if posted || held {
+		return errors.NewConflictError(errors.ErrDuplicateIdempotencyKey,
+			fmt.Sprintf("Idempotency key '%s' was already used", key),
+			map[string]any{"idempotency_key": key})
+	}
+	return nil
+}
+
+func negate(a money.Amount) money.Amount {
+	return amountOf(-a.Minor(), a.Currency())
+}
+
+func missingKey() errors.LayerError {
+	return errors.NewValidationError(errors.ErrMissingRequired, "Idempotency key is required",
+		map[string]any{"field": "idempotency_key"})
+}
+
+func unbalanced(key, reason string) errors.LayerError {
+	return errors.NewBusinessRuleError(errors.ErrInvalidBusinessRule,
+		fmt.Sprintf("Transaction '%s' is unbalanced: %s", key, reason),
+		map[string]any{"idempotency_key": key, "reason": reason})
+}
+
+func accountNotFound(id AccountID) errors.LayerError {
+	return errors.NewNotFoundError(errors.ErrResourceNotFound,
+		fmt.Sprintf("Account '%s' not found", id),
+		map[string]any{"account": string(id)})
+}
+
+func holdNotFound(key string) errors.LayerError {
+	return errors.NewNotFoundError(errors.ErrResourceNotFound,
+		fmt.Sprintf("Hold '%s' not found", key),
+		map[string]any{"hold": key})
+}
@@ -0,0 +1,237 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+)
+
+func mxn(s string) money.Amount {
+	return money.MustParse(s, "MXN")
+}
+
+func transfer(key string, from, to AccountID, amount string) Transaction {
+	return Transaction{Key: key, Postings: []Posting{
+		{Account: from, Amount: mxn("-" + amount)},
+		{Account: to, Amount: mxn(amount)},
+	}}
+}
+
+func newTestLedger(t *testing.T) *Ledger {
+	t.Helper()
+	l := New(NewMemoryStore(), clock.NewFake(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)))
+	ctx := context.Background()
+	for _, a := range []Account{
+		{ID: "bank", Currency: "MXN", AllowNegative: true},
+		{ID: "wallet", Currency: "MXN"},
+		{ID: "house", Currency: "MXN", AllowNegative: true},
+	} {
+		if err := l.OpenAccount(ctx, a); err != nil {
+			t.Fatalf("OpenAccount(%s) unexpected error: %v", a.ID, err)
+		}
+	}
+	if _, err := l.Post(ctx, transfer("deposit-1", "bank", "wallet", "100")); err != nil {
+		t.Fatalf("Post() unexpected error: %v", err)
+	}
+	return l
+}
+
+func TestLedger_Post(t *testing.T) {
+	tests := []struct {
+		name     string
+		tx       Transaction
+		wantCode errors.ErrorCode
+		wantType errors.ErrorType
+	}{
+		{"Stake within balance", transfer("stake-1", "wallet", "house", "60"), "", ""},
+		{"Stake of whole balance", transfer("stake-1", "wallet", "house", "100"), "", ""},
+		{"Insufficient funds", transfer("stake-1", "wallet", "house", "100.01"), errors.ErrInsufficientFunds, errors.BusinessRuleError},
+		{"Duplicate key", transfer("deposit-1", "bank", "wallet", "10"), errors.ErrDuplicateIdempotencyKey, errors.ConflictError},
+		{"Missing key", transfer("", "bank", "wallet", "10"), errors.ErrMissingRequired, errors.ValidationError},
+		{"Unbalanced", Transaction{Key: "x", Postings: []Posting{{"bank", mxn("-10")}, {"wallet", mxn("9")}}}, errors.ErrInvalidBusinessRule, errors.BusinessRuleError},
+		{"Single posting", Transaction{Key: "x", Postings: []Posting{{"bank", mxn("10")}}}, errors.ErrInvalidBusinessRule, errors.BusinessRuleError},
+		{"Unknown account", transfer("x", "bank", "ghost", "10"), errors.ErrResourceNotFound, errors.NotFoundError},
+		{"Currency mismatch", Transaction{Key: "x", Postings: []Posting{
+			{"bank", money.MustParse("-10", "USD")}, {"wallet", money.MustParse("10", "USD")},
+		}}, errors.ErrInvalidCurrency, errors.ValidationError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLedger(t)
+			_, err := l.Post(context.Background(), tt.tx)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("Post() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Post() expected %v", tt.wantCode)
+			}
+			if err.Code() != tt.wantCode || err.Type() != tt.wantType {
+				t.Errorf("error = %v/%v, want %v/%v", err.Code(), err.Type(), tt.wantCode, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestLedger_FailedPostChangesNothing(t *testing.T) {
+	l := newTestLedger(t)
+	ctx := context.Background()
+	if _, err := l.Post(ctx, transfer("stake-1", "wallet", "house", "500")); err == nil {
+		t.Fatal("Post() expected insufficient funds")
+	}
+	b, _ := l.Balance(ctx, "wallet")
+	if b.Total.String() != "100.00" {
+		t.Errorf("Balance = %s, want 100.00", b.Total)
+	}
+	if _, err := l.Transaction(ctx, "stake-1"); err == nil {
+		t.Error("Failed transaction should not be stored")
+	}
+}
+
+func TestLedger_Holds(t *testing.T) {
+	l := newTestLedger(t)
+	ctx := context.Background()
+
+	if _, err := l.Hold(ctx, "bet-1", "wallet", mxn("70")); err != nil {
+		t.Fatalf("Hold() unexpected error: %v", err)
+	}
+	b, _ := l.Balance(ctx, "wallet")
+	if b.Total.String() != "100.00" || b.Held.String() != "70.00" || b.Available.String() != "30.00" {
+		t.Errorf("Balance = %+v, want total 100, held 70, available 30", b)
+	}
+
+	_, err := l.Post(ctx, transfer("stake-2", "wallet", "house", "40"))
+	if err == nil || err.Code() != errors.ErrInsufficientFunds {
+		t.Fatalf("Post() error = %v, want %v", err, errors.ErrInsufficientFunds)
+	}
+	if err.Details()["available"] != "30.00" || err.Details()["required"] != "40.00" {
+		t.Errorf("Details() = %v, want available 30.00 and required 40.00", err.Details())
+	}
+
+	if _, err := l.Hold(ctx, "bet-1", "wallet", mxn("1")); err == nil || err.Code() != errors.ErrDuplicateIdempotencyKey {
+		t.Errorf("Hold() error = %v, want %v", err, errors.ErrDuplicateIdempotencyKey)
+	}
+
+	if _, err := l.Capture(ctx, "bet-1", transfer("bet-1", "wallet", "house", "70")); err != nil {
+		t.Fatalf("Capture() unexpected error: %v", err)
+	}
+	b, _ = l.Balance(ctx, "wallet")
+	if b.Total.String() != "30.00" || b.Held.String() != "0.00" {
+		t.Errorf("Balance = %+v, want total 30, held 0", b)
+	}
+
+	if err := l.Release(ctx, "bet-1"); err == nil || err.Code() != errors.ErrResourceNotFound {
+		t.Errorf("Release() error = %v, want %v", err, errors.ErrResourceNotFound)
+	}
+}
+
+func TestLedger_ReleaseRestoresFunds(t *testing.T) {
+	l := newTestLedger(t)
+	ctx := context.Background()
+	if _, err := l.Hold(ctx, "bet-1", "wallet", mxn("100")); err != nil {
+		t.Fatalf("Hold() unexpected error: %v", err)
+	}
+	if err := l.Release(ctx, "bet-1"); err != nil {
+		t.Fatalf("Release() unexpected error: %v", err)
+	}
+	if b, _ := l.Balance(ctx, "wallet"); b.Available.String() != "100.00" {
+		t.Errorf("Available = %s, want 100.00", b.Available)
+	}
+}
+
+func TestLedger_ConcurrentDebits(t *testing.T) {
+	l := newTestLedger(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := l.Post(ctx, transfer(fmt.Sprintf("stake-%d", i), "wallet", "house", "10")); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if succeeded != 10 {
+		t.Errorf("succeeded = %d, want 10", succeeded)
+	}
+	if b, _ := l.Balance(ctx, "wallet"); !b.Total.IsZero() {
+		t.Errorf("Balance = %s, want 0.00", b.Total)
+	}
+}
+
+// TestCatalogExamples keeps the catalog examples, which feed the OpenAPI and
+// error docs, in step with what the ledger returns.
+func TestCatalogExamples(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLedger(t)
+
+	tests := []struct {
+		name string
+		run  func() errors.LayerError
+	}{
+		{"Duplicate key", func() errors.LayerError {
+			_, err := l.Post(ctx, transfer("deposit-1", "bank", "wallet", "10"))
+			return err
+		}},
+		{"Account exists", func() errors.LayerError {
+			return l.OpenAccount(ctx, Account{ID: "wallet", Currency: "MXN"})
+		}},
+		{"Insufficient funds", func() errors.LayerError {
+			_, err := l.Post(ctx, transfer("bet-1", "wallet", "house", "500"))
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			matchesCatalog(t, err)
+		})
+	}
+}
+
+func matchesCatalog(t *testing.T, err errors.LayerError) {
+	t.Helper()
+	def, ok := errors.Lookup(err.Code())
+	if !ok {
+		t.Fatalf("%s is not in the catalog", err.Code())
+	}
+	want := def.Example()
+	if err.Error() != want.Error() || err.Type() != want.Type() || err.Layer() != want.Layer() {
+		t.Errorf("%s = %q (%s, %s), catalog example = %q (%s, %s)", err.Code(),
+			err.Error(), err.Type(), err.Layer(), want.Error(), want.Type(), want.Layer())
+	}
+	if got, exp := detailKeys(err), detailKeys(want); got != exp {
+		t.Errorf("%s details = [%s], catalog example = [%s]", err.Code(), got, exp)
+	}
+}
+
+func detailKeys(err errors.LayerError) string {
+	keys := make([]string, 0, len(err.Details()))
+	for k := range err.Details() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, " ")
+}
@@ -0,0 +1,156 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+)
+
+// Store persists accounts, transactions and holds. Update must run fn
+// atomically and in isolation from other updates, committing its writes only
+// when fn returns nil.
+type Store interface {
+	Update(ctx context.Context, fn func(tx Tx) errors.LayerError) errors.LayerError
+	View(ctx context.Context, fn func(tx Tx) errors.LayerError) errors.LayerError
+}
+
+// Tx is the view of the store inside Update or View. Reads see the state
+// committed before the call; writes become visible after it returns.
+type Tx interface {
+	Account(id AccountID) (Account, bool)
+	Balance(id AccountID) int64
+	Held(id AccountID) int64
+	Transaction(key string) (Transaction, bool)
+	Hold(key string) (Hold, bool)
+
+	PutAccount(account Account) errors.LayerError
+	PutTransaction(t Transaction) errors.LayerError
+	PutHold(h Hold) errors.LayerError
+	DeleteHold(key string) errors.LayerError
+}
+
+// MemoryStore is a Store kept in memory. It serializes updates with a mutex
+// and is safe for concurrent use.
+type MemoryStore struct {
+	mu           sync.RWMutex
+	accounts     map[AccountID]Account
+	balances     map[AccountID]int64
+	held         map[AccountID]int64
+	transactions map[string]Transaction
+	holds        map[string]Hold
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		accounts:     make(map[AccountID]Account),
+		balances:     make(map[AccountID]int64),
+		held:         make(map[AccountID]int64),
+		transactions: make(map[string]Transaction),
+		holds:        make(map[string]Hold),
+	}
+}
+
+func (s *MemoryStore) Update(ctx context.Context, fn func(tx Tx) errors.LayerError) errors.LayerError {
+	if err := ctx.Err(); err != nil {
+		return contextError(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx := &memoryTx{store: s, writable: true}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	for _, apply := range tx.writes {
+		apply()
+	}
+	return nil
+}
+
+func (s *MemoryStore) View(ctx context.Context, fn func(tx Tx) errors.LayerError) errors.LayerError {
+	if err := ctx.Err(); err != nil {
+		return contextError(err)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(&memoryTx{store: s})
+}
+
+type memoryTx struct {
+	store    *MemoryStore
+	writable bool
+	writes   []func()
+}
+
+func (tx *memoryTx) Account(id AccountID) (Account, bool) {
+	a, ok := tx.store.accounts[id]
+	return a, ok
+}
+
+func (tx *memoryTx) Balance(id AccountID) int64 {
+	return tx.store.balances[id]
+}
+
+func (tx *memoryTx) Held(id AccountID) int64 {
+	return tx.store.held[id]
+}
+
+func (tx *memoryTx) Transaction(key string) (Transaction, bool) {
+	t, ok := tx.store.transactions[key]
+	return t, ok
+}
+
+func (tx *memoryTx) Hold(key string) (Hold, bool) {
+	h, ok := tx.store.holds[key]
+	return h, ok
+}
+
+func (tx *memoryTx) PutAccount(account Account) errors.LayerError {
+	return tx.write(func() { tx.store.accounts[account.ID] = account })
+}
+
+func (tx *memoryTx) PutTransaction(t Transaction) errors.LayerError {
+	return tx.write(func() {
+		tx.store.transactions[t.Key] = t
+		for _, p := range t.Postings {
+			tx.store.balances[p.Account] += p.Amount.Minor()
+		}
+	})
+}
+
+func (tx *memoryTx) PutHold(h Hold) errors.LayerError {
+	return tx.write(func() {
+		tx.store.holds[h.Key] = h
+		tx.store.held[h.Account] += h.Amount.Minor()
+	})
+}
+
+func (tx *memoryTx) DeleteHold(key string) errors.LayerError {
+	return tx.write(func() {
+		if h, ok := tx.store.holds[key]; ok {
+			delete(tx.store.holds, key)
+			tx.store.held[h.Account] -= h.Amount.Minor()
+		}
+	})
+}
+
+func (tx *memoryTx) write(apply func()) errors.LayerError {
+	if !tx.writable {
+		return errors.NewInfrastructureError(errors.ErrRepositoryOperation, "Cannot write in a read-only ledger view")
+	}
+	tx.writes = append(tx.writes, apply)
+	return nil
+}
+
+func contextError(err error) errors.LayerError {
+	return errors.NewInfrastructureError(errors.ErrRepositoryOperation,
+		fmt.Sprintf("Ledger operation cancelled: %v", err))
+}
+
+// amountOf converts stored minor units to an amount in the account currency.
+func amountOf(minor int64, currency string) money.Amount {
+	a, _ := money.New(minor, currency)
+	return a
+}
@@ -0,0 +1,236 @@
+package limits
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+)
+
+// Period is the length of a rolling limit window.
+type Period string
+
+const (
+	Daily   Period = "daily"   // the last 24 hours
+	Weekly  Period = "weekly"  // the last 7 days
+	Monthly Period = "monthly" // since the same day of the previous month
+)
+
+var periods = []Period{Daily, Weekly, Monthly}
+
+// historyStart returns where the history is fetched from to cover the
+// longest window. Month-end normalization can stretch a month to 31 days
+// after a short month (Jan 31 + 1 month is Mar 3), so it goes 31 calendar
+// days back, in the location of now so that a DST change does not shorten
+// it, and evaluate drops what has expired.
+func historyStart(now time.Time) time.Time {
+	return now.AddDate(0, 0, -31)
+}
+
+// expiry returns when an entry recorded at t leaves the window.
+func (p Period) expiry(t time.Time) time.Time {
+	switch p {
+	case Weekly:
+		return t.AddDate(0, 0, 7)
+	case Monthly:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.Add(24 * time.Hour)
+}
+
+// EntryKind classifies ledger entries. Deposits count toward deposit limits;
+// stakes minus payouts count toward loss limits.
+type EntryKind string
+
+const (
+	Deposit EntryKind = "deposit"
+	Stake   EntryKind = "stake"
+	Payout  EntryKind = "payout"
+)
+
+type Entry struct {
+	Kind   EntryKind
+	Amount money.Amount
+	At     time.Time
+}
+
+// Ledger provides a user's history. Implementations return every entry
+// recorded at or after since.
+type Ledger interface {
+	Entries(ctx context.Context, userID string, since time.Time) ([]Entry, errors.LayerError)
+}
+
+// Limits are the self-imposed limits of a user. Missing periods and a zero
+// Session are unlimited. SessionCooldown is the break enforced after a
+// session reaches its limit.
+type Limits struct {
+	Deposit         map[Period]money.Amount
+	Loss            map[Period]money.Amount
+	Session         time.Duration
+	SessionCooldown time.Duration
+}
+
+type Checker struct {
+	ledger Ledger
+	clock  clock.Clock
+}
+
+func NewChecker(ledger Ledger, c clock.Clock) *Checker {
+	return &Checker{ledger: ledger, clock: c}
+}
+
+// CheckDeposit refuses a deposit that would exceed any deposit limit.
+func (c *Checker) CheckDeposit(ctx context.Context, userID string, amount money.Amount, limits Limits) errors.LayerError {
+	return c.check(ctx, userID, Deposit, amount, limits.Deposit)
+}
+
+// CheckStake refuses a stake whose full loss would exceed any loss limit.
+func (c *Checker) CheckStake(ctx context.Context, userID string, stake money.Amount, limits Limits) errors.LayerError {
+	return c.check(ctx, userID, Stake, stake, limits.Loss)
+}
+
+// CheckSession refuses activity once a session started at start has lasted
+// for the session limit. The user may play again at reset_at, after the
+// cooldown.
+func (c *Checker) CheckSession(start time.Time, limits Limits) errors.LayerError {
+	if limits.Session <= 0 {
+		return nil
+	}
+	now := c.clock.Now()
+	elapsed := now.Sub(start)
+	if elapsed < limits.Session {
+		return nil
+	}
+	return errors.NewBusinessRuleError(errors.ErrLimitExceeded,
+		fmt.Sprintf("Session time limit of %s reached", limits.Session),
+		map[string]any{
+			"limit":     "session",
+			"max":       limits.Session.String(),
+			"elapsed":   elapsed.Truncate(time.Second).String(),
+			"remaining": "0s",
+			"reset_at":  start.Add(limits.Session + limits.SessionCooldown),
+		})
+}
+
+func (c *Checker) check(ctx context.Context, userID string, kind EntryKind, amount money.Amount, limits map[Period]money.Amount) errors.LayerError {
+	if len(limits) == 0 {
+		return nil
+	}
+	now := c.clock.Now()
+	entries, err := c.ledger.Entries(ctx, userID, historyStart(now))
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].At.Before(entries[j].At) })
+
+	var worst *window
+	for _, period := range periods {
+		maxAmount, ok := limits[period]
+		if !ok {
+			continue
+		}
+		w, err := evaluate(kind, period, maxAmount, amount, entries, now)
+		if err != nil {
+			return err
+		}
+		if w.exceeded && (worst == nil || w.remaining < worst.remaining) {
+			worst = &w
+		}
+	}
+	if worst == nil {
+		return nil
+	}
+	limit := "deposit"
+	if kind == Stake {
+		limit = "loss"
+	}
+	remaining, _ := money.New(worst.remaining, amount.Currency())
+	details := map[string]any{
+		"limit":     limit,
+		"period":    string(worst.period),
+		"max":       worst.max.String(),
+		"used":      worst.used.String(),
+		"requested": amount.String(),
+		"remaining": remaining.String(),
+		"currency":  amount.Currency(),
+	}
+	// A request larger than the limit itself never fits, so it has no reset.
+	if !worst.resetAt.IsZero() && amount.Minor() <= worst.max.Minor() {
+		details["reset_at"] = worst.resetAt
+	}
+	return errors.NewBusinessRuleError(errors.ErrLimitExceeded,
+		fmt.Sprintf("%s %s limit of %s %s exceeded", worst.period, limit, worst.max, worst.max.Currency()),
+		details)
+}
+
+type window struct {
+	period    Period
+	max       money.Amount
+	used      money.Amount
+	remaining int64
+	exceeded  bool
+	resetAt   time.Time
+}
+
+// evaluate sums the entries inside the period window. When the request does
+// not fit, resetAt is the first moment at which enough entries have left the
+// window for it to fit, or the moment the window empties if it never can.
+func evaluate(kind EntryKind, period Period, limit, amount money.Amount, entries []Entry, now time.Time) (window, errors.LayerError) {
+	if amount.Currency() != limit.Currency() {
+		return window{}, currencyMismatch(amount.Currency(), limit.Currency())
+	}
+	var inWindow []Entry
+	var used int64
+	for _, e := range entries {
+		delta := contribution(kind, e)
+		if delta == 0 || !period.expiry(e.At).After(now) {
+			continue
+		}
+		if e.Amount.Currency() != limit.Currency() {
+			return window{}, currencyMismatch(e.Amount.Currency(), limit.Currency())
+		}
+		inWindow = append(inWindow, e)
+		used += delta
+	}
+
+	usedAmount, _ := money.New(max(used, 0), limit.Currency())
+	w := window{period: period, max: limit, used: usedAmount, remaining: max(limit.Minor()-max(used, 0), 0)}
+	if max(used, 0)+amount.Minor() <= limit.Minor() {
+		return w, nil
+	}
+
+	w.exceeded = true
+	for i, e := range inWindow {
+		used -= contribution(kind, e)
+		if i+1 < len(inWindow) && inWindow[i+1].At.Equal(e.At) {
+			continue
+		}
+		w.resetAt = period.expiry(e.At)
+		if max(used, 0)+amount.Minor() <= limit.Minor() {
+			break
+		}
+	}
+	return w, nil
+}
+
+func currencyMismatch(currency, expected string) errors.LayerError {
+	return errors.NewValidationError(errors.ErrInvalidCurrency,
+		fmt.Sprintf("Amount in %s cannot count toward a %s limit", currency, expected),
+		map[string]any{"currency": currency, "expected_currency": expected})
+}
+
+func contribution(kind EntryKind, e Entry) int64 {
+	switch {
+	case kind == Deposit && e.Kind == Deposit:
+		return e.Amount.Minor()
+	case kind == Stake && e.Kind == Stake:
+		return e.Amount.Minor()
+	case kind == Stake && e.Kind == Payout:
+		return -e.Amount.Minor()
+	}
+	return 0
+}
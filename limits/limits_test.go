@@ -0,0 +1,221 @@
+package limits
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+)
+
+type fakeLedger []Entry
+
+func (l fakeLedger) Entries(_ context.Context, _ string, since time.Time) ([]Entry, errors.LayerError) {
+	var out []Entry
+	for _, e := range l {
+		if !e.At.Before(since) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+var now = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+func mxn(s string) money.Amount {
+	return money.MustParse(s, "MXN")
+}
+
+func entry(kind EntryKind, amount string, ago time.Duration) Entry {
+	return Entry{Kind: kind, Amount: mxn(amount), At: now.Add(-ago)}
+}
+
+func TestCheckDeposit(t *testing.T) {
+	limits := Limits{Deposit: map[Period]money.Amount{
+		Daily:   mxn("1000"),
+		Weekly:  mxn("3000"),
+		Monthly: mxn("5000"),
+	}}
+
+	tests := []struct {
+		name          string
+		ledger        fakeLedger
+		amount        string
+		wantPeriod    string
+		wantRemaining string
+		wantResetAt   time.Time
+	}{
+		{
+			name:   "Within every limit",
+			ledger: fakeLedger{entry(Deposit, "400", 2*time.Hour)},
+			amount: "600",
+		},
+		{
+			name:          "Daily limit exceeded",
+			ledger:        fakeLedger{entry(Deposit, "400", 10*time.Hour), entry(Deposit, "300", 2*time.Hour)},
+			amount:        "500",
+			wantPeriod:    "daily",
+			wantRemaining: "300.00",
+			wantResetAt:   now.Add(14 * time.Hour),
+		},
+		{
+			name:   "Entries older than a day leave the daily window",
+			ledger: fakeLedger{entry(Deposit, "900", 25*time.Hour)},
+			amount: "1000",
+		},
+		{
+			name: "Weekly limit exceeded",
+			ledger: fakeLedger{
+				entry(Deposit, "1000", 6*24*time.Hour),
+				entry(Deposit, "1000", 4*24*time.Hour),
+				entry(Deposit, "800", 2*24*time.Hour),
+			},
+			amount:        "500",
+			wantPeriod:    "weekly",
+			wantRemaining: "200.00",
+			wantResetAt:   now.Add(24 * time.Hour),
+		},
+		{
+			name: "Monthly limit exceeded",
+			ledger: fakeLedger{
+				entry(Deposit, "2500", 20*24*time.Hour),
+				entry(Deposit, "2400", 10*24*time.Hour),
+			},
+			amount:        "200",
+			wantPeriod:    "monthly",
+			wantRemaining: "100.00",
+			wantResetAt:   now.Add(-20*24*time.Hour).AddDate(0, 1, 0),
+		},
+		{
+			name:          "Request larger than the limit has no reset",
+			ledger:        fakeLedger{},
+			amount:        "1500",
+			wantPeriod:    "daily",
+			wantRemaining: "1000.00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(tt.ledger, clock.NewFake(now))
+			err := checker.CheckDeposit(context.Background(), "user-1", mxn(tt.amount), limits)
+			if tt.wantPeriod == "" {
+				if err != nil {
+					t.Fatalf("CheckDeposit() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("CheckDeposit() expected limit error")
+			}
+			if err.Code() != errors.ErrLimitExceeded || err.Type() != errors.BusinessRuleError {
+				t.Errorf("error = %v/%v, want %v/%v", err.Code(), err.Type(), errors.ErrLimitExceeded, errors.BusinessRuleError)
+			}
+			details := err.Details()
+			if details["period"] != tt.wantPeriod {
+				t.Errorf("Details[period] = %v, want %v", details["period"], tt.wantPeriod)
+			}
+			if details["remaining"] != tt.wantRemaining {
+				t.Errorf("Details[remaining] = %v, want %v", details["remaining"], tt.wantRemaining)
+			}
+			resetAt, ok := details["reset_at"].(time.Time)
+			if tt.wantResetAt.IsZero() {
+				if ok {
+					t.Errorf("Details[reset_at] = %v, want none", resetAt)
+				}
+			} else if !resetAt.Equal(tt.wantResetAt) {
+				t.Errorf("Details[reset_at] = %v, want %v", details["reset_at"], tt.wantResetAt)
+			}
+		})
+	}
+}
+
+func TestCheckDeposit_MonthEnd(t *testing.T) {
+	// A deposit on Jan 31 stays in the monthly window until Mar 3, because
+	// Jan 31 + 1 month normalizes past the end of February.
+	at := time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC)
+	ledger := fakeLedger{{Kind: Deposit, Amount: mxn("900"), At: at}}
+	limits := Limits{Deposit: map[Period]money.Amount{Monthly: mxn("1000")}}
+	fake := clock.NewFake(time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC))
+	checker := NewChecker(ledger, fake)
+
+	err := checker.CheckDeposit(context.Background(), "user-1", mxn("200"), limits)
+	if err == nil {
+		t.Fatal("CheckDeposit() expected monthly limit error on Mar 1")
+	}
+	if got, want := err.Details()["reset_at"], time.Date(2023, 3, 3, 12, 0, 0, 0, time.UTC); got != want {
+		t.Errorf("Details[reset_at] = %v, want %v", got, want)
+	}
+
+	fake.Set(time.Date(2023, 3, 3, 12, 0, 0, 0, time.UTC))
+	if err := checker.CheckDeposit(context.Background(), "user-1", mxn("200"), limits); err != nil {
+		t.Errorf("CheckDeposit() unexpected error on Mar 3: %v", err)
+	}
+}
+
+func TestCheckDeposit_MonthAcrossDST(t *testing.T) {
+	// Oct 31 to Dec 1 in New York is 31 days and one hour, since clocks go
+	// back on Nov 5.
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	ledger := fakeLedger{{Kind: Deposit, Amount: mxn("900"), At: time.Date(2023, 10, 31, 12, 0, 0, 0, ny)}}
+	limits := Limits{Deposit: map[Period]money.Amount{Monthly: mxn("1000")}}
+	checker := NewChecker(ledger, clock.NewFake(time.Date(2023, 12, 1, 11, 30, 0, 0, ny)))
+
+	if err := checker.CheckDeposit(context.Background(), "user-1", mxn("200"), limits); err == nil {
+		t.Error("CheckDeposit() expected monthly limit error half an hour before the deposit expires")
+	}
+}
+
+func TestCheckStake_NetLoss(t *testing.T) {
+	limits := Limits{Loss: map[Period]money.Amount{Daily: mxn("500")}}
+	ledger := fakeLedger{
+		entry(Stake, "400", 5*time.Hour),
+		entry(Payout, "300", 4*time.Hour),
+		entry(Stake, "200", 3*time.Hour),
+	}
+	checker := NewChecker(ledger, clock.NewFake(now))
+
+	// Net loss is 400 - 300 + 200 = 300, so 200 more fits and 201 does not.
+	if err := checker.CheckStake(context.Background(), "user-1", mxn("200"), limits); err != nil {
+		t.Errorf("CheckStake() unexpected error: %v", err)
+	}
+	err := checker.CheckStake(context.Background(), "user-1", mxn("201"), limits)
+	if err == nil {
+		t.Fatal("CheckStake() expected loss limit error")
+	}
+	if err.Details()["limit"] != "loss" || err.Details()["used"] != "300.00" {
+		t.Errorf("Details() = %v, want loss limit with 300.00 used", err.Details())
+	}
+}
+
+func TestCheckSession(t *testing.T) {
+	fake := clock.NewFake(now)
+	checker := NewChecker(fakeLedger{}, fake)
+	limits := Limits{Session: time.Hour, SessionCooldown: 30 * time.Minute}
+
+	if err := checker.CheckSession(now, limits); err != nil {
+		t.Fatalf("CheckSession() unexpected error: %v", err)
+	}
+	fake.Advance(time.Hour)
+	err := checker.CheckSession(now, limits)
+	if err == nil {
+		t.Fatal("CheckSession() expected session limit error")
+	}
+	if got := err.Details()["reset_at"]; got != now.Add(90*time.Minute) {
+		t.Errorf("Details[reset_at] = %v, want %v", got, now.Add(90*time.Minute))
+	}
+}
+
+func TestCheck_CurrencyMismatch(t *testing.T) {
+	limits := Limits{Deposit: map[Period]money.Amount{Daily: mxn("1000")}}
+	checker := NewChecker(fakeLedger{}, clock.NewFake(now))
+	err := checker.CheckDeposit(context.Background(), "user-1", money.MustParse("10", "USD"), limits)
+	if err == nil || err.Code() != errors.ErrInvalidCurrency {
+		t.Errorf("CheckDeposit() error = %v, want %v", err, errors.ErrInvalidCurrency)
+	}
+}
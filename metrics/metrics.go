@@ -0,0 +1,233 @@
+// Package metrics counts LayerErrors and measures HTTP handler latency, and
+// exposes both in the Prometheus text exposition format without external
+// dependencies.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// OverflowValue replaces label values beyond Options.MaxLabelValues.
+const OverflowValue = "other"
+
+// DefaultBuckets are the latency histogram bounds, in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Options configures a Recorder. Zero values take the documented defaults.
+type Options struct {
+	// Namespace prefixes every metric name. Defaults to "betmates".
+	Namespace string
+	// Buckets are the upper bounds of the latency histogram, in seconds.
+	// Defaults to DefaultBuckets.
+	Buckets []float64
+	// MaxLabelValues caps the distinct values recorded per label of each
+	// metric, such as code or route. Later values are reported as OverflowValue, so a flood
+	// of unknown routes or custom codes cannot exhaust memory. Defaults to
+	// 100.
+	MaxLabelValues int
+	// Clock times requests in Middleware. Defaults to clock.System().
+	Clock clock.Clock
+}
+
+// Recorder holds the metrics. It is safe for concurrent use.
+type Recorder struct {
+	opts Options
+
+	mu        sync.Mutex
+	errors    map[string]*counter
+	durations map[string]*histogram
+	values    map[label]map[string]bool
+}
+
+// label identifies a label of one metric, so metrics sharing a label name,
+// such as status, have separate caps.
+type label struct{ metric, name string }
+
+type counter struct {
+	labels []string
+	value  float64
+}
+
+type histogram struct {
+	labels []string
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+var (
+	errorLabels    = []string{"layer", "type", "code", "protocol", "status"}
+	durationLabels = []string{"route", "method", "status"}
+)
+
+// New returns an empty Recorder.
+func New(opts Options) *Recorder {
+	if opts.Namespace == "" {
+		opts.Namespace = "betmates"
+	}
+	if len(opts.Buckets) == 0 {
+		opts.Buckets = DefaultBuckets
+	}
+	opts.Buckets = append([]float64(nil), opts.Buckets...)
+	sort.Float64s(opts.Buckets)
+	if opts.MaxLabelValues <= 0 {
+		opts.MaxLabelValues = 100
+	}
+	if opts.Clock == nil {
+		opts.Clock = clock.System()
+	}
+	return &Recorder{
+		opts:      opts,
+		errors:    map[string]*counter{},
+		durations: map[string]*histogram{},
+		values:    map[label]map[string]bool{},
+	}
+}
+
+// Record counts err as handled by protocol, such as "http" or "grpc", with
+// the protocol's status: the HTTP status or the gRPC code. A nil err is
+// ignored.
+func (r *Recorder) Record(protocol string, status int, err errors.LayerError) {
+	if err == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	labels := r.limit("errors", errorLabels, []string{
+		string(err.Layer()), string(err.Type()), string(err.Code()), protocol, strconv.Itoa(status),
+	})
+	key := strings.Join(labels, "\xff")
+	c, ok := r.errors[key]
+	if !ok {
+		c = &counter{labels: labels}
+		r.errors[key] = c
+	}
+	c.value++
+}
+
+// Observe adds a request duration, in seconds, to the latency histogram.
+func (r *Recorder) Observe(route, method string, status int, seconds float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	labels := r.limit("durations", durationLabels, []string{route, method, strconv.Itoa(status)})
+	key := strings.Join(labels, "\xff")
+	h, ok := r.durations[key]
+	if !ok {
+		h = &histogram{labels: labels, counts: make([]uint64, len(r.opts.Buckets))}
+		r.durations[key] = h
+	}
+	for i, bound := range r.opts.Buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// limit replaces values not yet seen for their label of metric with
+// OverflowValue once the label has MaxLabelValues distinct values. Called
+// with mu held.
+func (r *Recorder) limit(metric string, names, values []string) []string {
+	for i, name := range names {
+		key := label{metric: metric, name: name}
+		seen, ok := r.values[key]
+		if !ok {
+			seen = map[string]bool{}
+			r.values[key] = seen
+		}
+		if seen[values[i]] {
+			continue
+		}
+		if len(seen) >= r.opts.MaxLabelValues {
+			values[i] = OverflowValue
+			continue
+		}
+		seen[values[i]] = true
+	}
+	return values
+}
+
+// WriteTo writes every metric in the Prometheus text exposition format,
+// with series sorted by label values.
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	var b strings.Builder
+	errorsName := r.opts.Namespace + "_errors_total"
+	fmt.Fprintf(&b, "# HELP %s LayerErrors handled, by layer, type, code, protocol and status.\n", errorsName)
+	fmt.Fprintf(&b, "# TYPE %s counter\n", errorsName)
+	for _, key := range sortedKeys(r.errors) {
+		c := r.errors[key]
+		fmt.Fprintf(&b, "%s%s %s\n", errorsName, labelSet(errorLabels, c.labels), formatFloat(c.value))
+	}
+
+	durationName := r.opts.Namespace + "_http_request_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %s HTTP request latency, by route, method and status.\n", durationName)
+	fmt.Fprintf(&b, "# TYPE %s histogram\n", durationName)
+	for _, key := range sortedKeys(r.durations) {
+		h := r.durations[key]
+		names := with(durationLabels, "le")
+		for i, bound := range r.opts.Buckets {
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", durationName, labelSet(names, with(h.labels, formatFloat(bound))), h.counts[i])
+		}
+		fmt.Fprintf(&b, "%s_bucket%s %d\n", durationName, labelSet(names, with(h.labels, "+Inf")), h.count)
+		fmt.Fprintf(&b, "%s_sum%s %s\n", durationName, labelSet(durationLabels, h.labels), formatFloat(h.sum))
+		fmt.Fprintf(&b, "%s_count%s %d\n", durationName, labelSet(durationLabels, h.labels), h.count)
+	}
+	r.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Handler serves the metrics for a Prometheus scraper.
+func (r *Recorder) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = r.WriteTo(w)
+	})
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labelSet(names, values []string) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + `="` + labelEscaper.Replace(values[i]) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// with returns a copy of values with v appended.
+func with(values []string, v string) []string {
+	return append(append(make([]string, 0, len(values)+1), values...), v)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
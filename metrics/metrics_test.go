@@ -0,0 +1,185 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/protocols"
+)
+
+func scrape(t *testing.T, r *Recorder) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	return rec.Body.String()
+}
+
+func assertLines(t *testing.T, body string, want ...string) {
+	t.Helper()
+	for _, line := range want {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("missing line %q in:\n%s", line, body)
+		}
+	}
+}
+
+func TestRecorder_HTTPErrorHandler(t *testing.T) {
+	r := New(Options{})
+	handler := r.HTTPErrorHandler(protocols.NewDefaultHTTPErrorHandler())
+
+	for i := 0; i < 2; i++ {
+		protocols.WriteHTTPError(httptest.NewRecorder(), handler,
+			errors.NewRateLimitError(errors.ErrRateLimited, "Rate limit exceeded"))
+	}
+	protocols.WriteHTTPError(httptest.NewRecorder(), handler,
+		errors.NewInfrastructureError(errors.ErrRepositoryOperation, "Repository operation failed"))
+
+	assertLines(t, scrape(t, r),
+		"# TYPE betmates_errors_total counter",
+		`betmates_errors_total{layer="application",type="rate_limit",code="RATE_LIMITED",protocol="http",status="429"} 2`,
+		`betmates_errors_total{layer="infrastructure",type="infrastructure",code="REPOSITORY_OPERATION",protocol="http",status="424"} 1`,
+	)
+}
+
+func TestRecorder_GRPCErrorHandler(t *testing.T) {
+	r := New(Options{Namespace: "wallet"})
+	handler := r.GRPCErrorHandler(protocols.NewDefaultGRPCErrorHandler())
+
+	response := handler.HandleGRPCError(errors.NewNotFoundError(errors.ErrUserNotFound, "User not found"))
+	if response.GRPCCode != 5 {
+		t.Errorf("GRPCCode = %d, want 5", response.GRPCCode)
+	}
+	assertLines(t, scrape(t, r),
+		`wallet_errors_total{layer="domain",type="not_found",code="USER_NOT_FOUND",protocol="grpc",status="5"} 1`,
+	)
+}
+
+func TestRecorder_MaxLabelValues(t *testing.T) {
+	r := New(Options{MaxLabelValues: 2})
+	for _, code := range []errors.ErrorCode{"CODE_A", "CODE_B", "CODE_C", "CODE_D", "CODE_A"} {
+		r.Record("http", 400, errors.NewValidationError(code, "Invalid"))
+	}
+
+	body := scrape(t, r)
+	assertLines(t, body,
+		`betmates_errors_total{layer="application",type="validation",code="CODE_A",protocol="http",status="400"} 2`,
+		`betmates_errors_total{layer="application",type="validation",code="CODE_B",protocol="http",status="400"} 1`,
+		`betmates_errors_total{layer="application",type="validation",code="other",protocol="http",status="400"} 2`,
+	)
+	if strings.Contains(body, "CODE_C") {
+		t.Error("codes beyond the limit must be reported as other")
+	}
+}
+
+func TestRecorder_MaxLabelValuesPerMetric(t *testing.T) {
+	r := New(Options{MaxLabelValues: 1})
+	r.Record("http", 400, errors.NewValidationError(errors.ErrInvalidFormat, "Invalid"))
+	r.Observe("GET /bets", http.MethodGet, 200, 0.01)
+
+	assertLines(t, scrape(t, r),
+		`betmates_errors_total{layer="application",type="validation",code="INVALID_FORMAT",protocol="http",status="400"} 1`,
+		`betmates_http_request_duration_seconds_count{route="GET /bets",method="GET",status="200"} 1`,
+	)
+}
+
+func TestRecorder_MiddlewareStreaming(t *testing.T) {
+	r := New(Options{})
+	handler := r.Middleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("ResponseWriter does not implement http.Flusher")
+		}
+		_, _ = w.Write([]byte("data: 1\n\n"))
+		flusher.Flush()
+		if _, _, err := w.(http.Hijacker).Hijack(); err == nil {
+			t.Error("Hijack() on a recorder must fail")
+		}
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+	if !rec.Flushed {
+		t.Error("Flush() was not forwarded")
+	}
+}
+
+func TestRecorder_Middleware(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC))
+	r := New(Options{Clock: fake, Buckets: []float64{0.1, 1}})
+	errorsHandler := r.HTTPErrorHandler(protocols.NewDefaultHTTPErrorHandler())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /bets/{id}", func(w http.ResponseWriter, req *http.Request) {
+		fake.Advance(50 * time.Millisecond)
+		if req.PathValue("id") == "missing" {
+			protocols.WriteHTTPError(w, errorsHandler, errors.NewNotFoundError(errors.ErrResourceNotFound, "Bet not found"))
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("POST /bets", func(w http.ResponseWriter, req *http.Request) {
+		fake.Advance(2 * time.Second)
+		panic("boom")
+	})
+	handler := r.Middleware(nil)(mux)
+
+	for _, target := range []string{"/bets/1", "/bets/2", "/bets/missing", "/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Middleware must re-raise panics")
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/bets", nil))
+	}()
+
+	assertLines(t, scrape(t, r),
+		"# TYPE betmates_http_request_duration_seconds histogram",
+		`betmates_http_request_duration_seconds_bucket{route="GET /bets/{id}",method="GET",status="200",le="0.1"} 2`,
+		`betmates_http_request_duration_seconds_bucket{route="GET /bets/{id}",method="GET",status="200",le="+Inf"} 2`,
+		`betmates_http_request_duration_seconds_sum{route="GET /bets/{id}",method="GET",status="200"} 0.1`,
+		`betmates_http_request_duration_seconds_count{route="GET /bets/{id}",method="GET",status="404"} 1`,
+		`betmates_http_request_duration_seconds_count{route="unmatched",method="GET",status="404"} 1`,
+		`betmates_http_request_duration_seconds_bucket{route="POST /bets",method="POST",status="500",le="1"} 0`,
+		`betmates_http_request_duration_seconds_bucket{route="POST /bets",method="POST",status="500",le="+Inf"} 1`,
+		`betmates_errors_total{layer="domain",type="not_found",code="RESOURCE_NOT_FOUND",protocol="http",status="404"} 1`,
+	)
+}
+
+func TestRoute(t *testing.T) {
+	r := New(Options{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /bets/{id}", func(w http.ResponseWriter, req *http.Request) {})
+	// Replaces the request, as auth.Middleware does.
+	withValue := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), struct{}{}, "v")))
+		})
+	}
+
+	r.Middleware(nil)(withValue(Route(mux))).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/bets/1", nil))
+	r.Middleware(nil)(withValue(mux)).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/bets/2", nil))
+
+	assertLines(t, scrape(t, r),
+		`betmates_http_request_duration_seconds_count{route="GET /bets/{id}",method="GET",status="200"} 1`,
+		`betmates_http_request_duration_seconds_count{route="unmatched",method="GET",status="200"} 1`,
+	)
+}
+
+func TestLabelEscaping(t *testing.T) {
+	r := New(Options{})
+	r.Observe("a\"b\\c\nd", http.MethodGet, 200, 0.2)
+	assertLines(t, scrape(t, r),
+		`betmates_http_request_duration_seconds_count{route="a\"b\\c\nd",method="GET",status="200"} 1`,
+	)
+}
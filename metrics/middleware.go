@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/protocols"
+)
+
+// RouteFunc derives the route label of a request. It runs after the
+// wrapped handler, so it can read values set while routing.
+type RouteFunc func(r *http.Request) string
+
+// ByPattern labels requests with the http.ServeMux pattern that matched
+// them, such as "POST /bets/{id}", and "unmatched" otherwise. Raw paths
+// would make a label value per ID.
+//
+// The mux sets the pattern on the request it receives, so ByPattern only
+// sees it when Middleware wraps the mux directly. When middleware in between
+// replaces the request, as auth.Middleware does with r.WithContext, wrap the
+// mux in Route to pass the pattern back.
+func ByPattern(r *http.Request) string {
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+	if m, ok := r.Context().Value(matchKey{}).(*match); ok && m.pattern != "" {
+		return m.pattern
+	}
+	return "unmatched"
+}
+
+// match carries the pattern found by Route back to Middleware.
+type match struct {
+	pattern string
+}
+
+type matchKey struct{}
+
+// Route wraps a mux so the pattern it matched reaches the route label of an
+// enclosing Middleware, whatever sits between the two:
+//
+//	m.Middleware(nil)(auth.Middleware(verifier, nil)(metrics.Route(mux)))
+func Route(mux http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Deferred so that the pattern is kept when the handler panics.
+		defer func() {
+			if m, ok := req.Context().Value(matchKey{}).(*match); ok {
+				m.pattern = req.Pattern
+			}
+		}()
+		mux.ServeHTTP(w, req)
+	})
+}
+
+// Middleware observes the latency of every request by route, method and
+// status. route defaults to ByPattern; see Route when other middleware sits
+// between this one and the mux. A panic is observed as a 500, unless
+// a status was already written, and re-raised. Errors are counted by the
+// handler returned from HTTPErrorHandler, so wrap the handler passed to
+// protocols.WriteHTTPError as well.
+func (r *Recorder) Middleware(route RouteFunc) func(http.Handler) http.Handler {
+	if route == nil {
+		route = ByPattern
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			start := r.opts.Clock.Now()
+			req = req.WithContext(context.WithValue(req.Context(), matchKey{}, &match{}))
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				p := recover()
+				if p != nil && !sw.wroteHeader {
+					sw.status = http.StatusInternalServerError
+				}
+				r.Observe(route(req), req.Method, sw.status, r.opts.Clock.Now().Sub(start).Seconds())
+				if p != nil {
+					panic(p)
+				}
+			}()
+			next.ServeHTTP(sw, req)
+		})
+	}
+}
+
+// statusWriter records the status written by a handler.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends buffered data to the client, writing the status first if the
+// handler has not. It does nothing when the underlying writer cannot flush.
+func (w *statusWriter) Flush() {
+	w.wroteHeader = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack hands the connection over to the handler, or fails with
+// http.ErrNotSupported when the underlying writer cannot be hijacked.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// HTTPErrorHandler wraps h so every error it maps is counted with protocol
+// "http" and the mapped status.
+func (r *Recorder) HTTPErrorHandler(h protocols.HTTPErrorHandler) protocols.HTTPErrorHandler {
+	return &httpErrorHandler{HTTPErrorHandler: h, recorder: r}
+}
+
+type httpErrorHandler struct {
+	protocols.HTTPErrorHandler
+	recorder *Recorder
+}
+
+func (h *httpErrorHandler) HandleHTTPError(err errors.LayerError) protocols.HTTPErrorResponse {
+	response := h.HTTPErrorHandler.HandleHTTPError(err)
+	h.recorder.Record("http", response.HTTPStatus, err)
+	return response
+}
+
+// GRPCErrorHandler wraps h so every error it maps is counted with protocol
+// "grpc" and the mapped gRPC code as status.
+func (r *Recorder) GRPCErrorHandler(h protocols.GRPCErrorHandler) protocols.GRPCErrorHandler {
+	return &grpcErrorHandler{GRPCErrorHandler: h, recorder: r}
+}
+
+type grpcErrorHandler struct {
+	protocols.GRPCErrorHandler
+	recorder *Recorder
+}
+
+func (h *grpcErrorHandler) HandleGRPCError(err errors.LayerError) protocols.GRPCErrorResponse {
+	response := h.GRPCErrorHandler.HandleGRPCError(err)
+	h.recorder.Record("grpc", response.GRPCCode, err)
+	return response
+}
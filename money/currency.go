@@ -0,0 +1,82 @@
+package money
+
+import (
+	"sort"
+	"strings"
+)
+
+// exponents maps the active ISO 4217 alphabetic codes to their number of
+// minor-unit digits. Codes without minor units in the standard (precious
+// metals, SDR, testing codes) map to -1 and cannot be used for amounts.
+var exponents = func() map[string]int {
+	table := make(map[string]int)
+	for _, code := range strings.Fields(`
+		AED AFN ALL AMD ANG AOA ARS AUD AWG AZN
+		BAM BBD BDT BGN BMD BND BOB BOV BRL BSD BTN BWP BYN BZD
+		CAD CDF CHE CHF CHW CNY COP COU CRC CUC CUP CVE CZK
+		DKK DOP DZD
+		EGP ERN ETB EUR
+		FJD FKP
+		GBP GEL GHS GIP GMD GTQ GYD
+		HKD HNL HTG HUF
+		IDR ILS INR IRR
+		JMD
+		KES KGS KHR KPW KYD KZT
+		LAK LBP LKR LRD LSL
+		MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN
+		NAD NGN NIO NOK NPR NZD
+		PAB PEN PGK PHP PKR PLN
+		QAR
+		RON RSD RUB
+		SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL
+		THB TJS TMT TOP TRY TTD TWD TZS
+		UAH USD USN UYU UZS
+		VED VES
+		WST
+		XCD XCG
+		YER
+		ZAR ZMW ZWG`) {
+		table[code] = 2
+	}
+	for _, code := range strings.Fields(`BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF`) {
+		table[code] = 0
+	}
+	for _, code := range strings.Fields(`BHD IQD JOD KWD LYD OMR TND`) {
+		table[code] = 3
+	}
+	for _, code := range strings.Fields(`CLF UYW`) {
+		table[code] = 4
+	}
+	for _, code := range strings.Fields(`XAG XAU XBA XBB XBC XBD XDR XPD XPT XSU XTS XUA XXX`) {
+		table[code] = -1
+	}
+	return table
+}()
+
+// IsCurrency reports whether code is an active ISO 4217 alphabetic code.
+func IsCurrency(code string) bool {
+	_, ok := exponents[code]
+	return ok
+}
+
+// Currencies returns the active ISO 4217 alphabetic codes in alphabetical
+// order.
+func Currencies() []string {
+	codes := make([]string, 0, len(exponents))
+	for code := range exponents {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Exponent returns the number of minor-unit digits of a currency, e.g. 2 for
+// MXN and 0 for JPY. It reports false for unknown codes and for codes that
+// have no minor unit.
+func Exponent(code string) (int, bool) {
+	exp, ok := exponents[code]
+	if !ok || exp < 0 {
+		return 0, false
+	}
+	return exp, true
+}
@@ -0,0 +1,181 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+var amountRegex = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// Amount is an exact monetary value held as an integer number of minor units
+// (cents for MXN, yen for JPY). The zero value is not usable; build amounts
+// with New, Parse or Zero.
+type Amount struct {
+	minor    int64
+	currency string
+}
+
+// New builds an amount from minor units.
+func New(minor int64, currency string) (Amount, errors.LayerError) {
+	if _, ok := Exponent(currency); !ok {
+		return Amount{}, invalidCurrency(currency)
+	}
+	return Amount{minor: minor, currency: currency}, nil
+}
+
+// Zero returns a zero amount in the currency.
+func Zero(currency string) (Amount, errors.LayerError) {
+	return New(0, currency)
+}
+
+// Parse reads a plain decimal string such as "150.50" or "-3". Thousands
+// separators, exponents, a leading "+", surrounding spaces and more decimal
+// places than the currency allows are rejected with ErrInvalidAmount.
+func Parse(value, currency string) (Amount, errors.LayerError) {
+	exp, ok := Exponent(currency)
+	if !ok {
+		return Amount{}, invalidCurrency(currency)
+	}
+	if !amountRegex.MatchString(value) {
+		return Amount{}, invalidAmount(value, currency, "malformed amount")
+	}
+	if Precision(value) > exp {
+		return Amount{}, errors.NewValidationError(errors.ErrInvalidAmount,
+			fmt.Sprintf("Amount '%s' has more than %d decimal places for %s", value, exp, currency),
+			map[string]any{"value": value, "currency": currency, "reason": "too many decimal places", "max_precision": exp})
+	}
+
+	digits := strings.TrimPrefix(value, "-")
+	whole, frac, _ := strings.Cut(digits, ".")
+	frac += strings.Repeat("0", exp-len(frac))
+	minor, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Amount{}, invalidAmount(value, currency, "amount out of range")
+	}
+	if strings.HasPrefix(value, "-") {
+		minor = -minor
+	}
+	return Amount{minor: minor, currency: currency}, nil
+}
+
+// MustParse is like Parse but panics on error. It is intended for
+// configuration constants and tests.
+func MustParse(value, currency string) Amount {
+	a, err := Parse(value, currency)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Precision returns the number of decimal places written in value.
+func Precision(value string) int {
+	if _, frac, ok := strings.Cut(value, "."); ok {
+		return len(frac)
+	}
+	return 0
+}
+
+func (a Amount) Minor() int64 {
+	return a.minor
+}
+
+func (a Amount) Currency() string {
+	return a.currency
+}
+
+func (a Amount) IsZero() bool {
+	return a.minor == 0
+}
+
+func (a Amount) IsPositive() bool {
+	return a.minor > 0
+}
+
+func (a Amount) IsNegative() bool {
+	return a.minor < 0
+}
+
+// Cmp compares two amounts of the same currency like strings.Compare.
+func (a Amount) Cmp(other Amount) (int, errors.LayerError) {
+	if err := a.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case a.minor < other.minor:
+		return -1, nil
+	case a.minor > other.minor:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (a Amount) Add(other Amount) (Amount, errors.LayerError) {
+	if err := a.sameCurrency(other); err != nil {
+		return Amount{}, err
+	}
+	sum := a.minor + other.minor
+	if (other.minor > 0 && sum < a.minor) || (other.minor < 0 && sum > a.minor) {
+		return Amount{}, invalidAmount(a.String(), a.currency, "amount out of range")
+	}
+	return Amount{minor: sum, currency: a.currency}, nil
+}
+
+func (a Amount) Sub(other Amount) (Amount, errors.LayerError) {
+	if other.minor == math.MinInt64 {
+		return Amount{}, invalidAmount(other.String(), other.currency, "amount out of range")
+	}
+	return a.Add(Amount{minor: -other.minor, currency: other.currency})
+}
+
+// MulRat multiplies the amount by an exact factor, such as decimal odds, and
+// rounds toward zero to the currency's minor unit so payouts never exceed the
+// exact value.
+func (a Amount) MulRat(r *big.Rat) (Amount, errors.LayerError) {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(a.minor), r)
+	minor := new(big.Int).Quo(product.Num(), product.Denom())
+	if !minor.IsInt64() {
+		return Amount{}, invalidAmount(a.String(), a.currency, "amount out of range")
+	}
+	return Amount{minor: minor.Int64(), currency: a.currency}, nil
+}
+
+// Rat returns the exact value in major units.
+func (a Amount) Rat() *big.Rat {
+	exp, _ := Exponent(a.currency)
+	return new(big.Rat).SetFrac(big.NewInt(a.minor), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+}
+
+// String renders the amount in major units with the currency's precision,
+// e.g. "150.50" for MXN and "150" for JPY.
+func (a Amount) String() string {
+	exp, _ := Exponent(a.currency)
+	return a.Rat().FloatString(exp)
+}
+
+func (a Amount) sameCurrency(other Amount) errors.LayerError {
+	if a.currency != other.currency {
+		return errors.NewValidationError(errors.ErrInvalidCurrency,
+			fmt.Sprintf("Cannot combine %s and %s amounts", a.currency, other.currency),
+			map[string]any{"currency": a.currency, "other_currency": other.currency})
+	}
+	return nil
+}
+
+func invalidCurrency(currency string) errors.LayerError {
+	return errors.NewValidationError(errors.ErrInvalidCurrency,
+		fmt.Sprintf("Currency '%s' is not supported for amounts", currency),
+		map[string]any{"currency": currency})
+}
+
+func invalidAmount(value, currency, reason string) errors.LayerError {
+	return errors.NewValidationError(errors.ErrInvalidAmount,
+		fmt.Sprintf("Invalid %s amount '%s': %s", currency, value, reason),
+		map[string]any{"value": value, "currency": currency, "reason": reason})
+}
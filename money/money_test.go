@@ -0,0 +1,113 @@
+package money
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		currency  string
+		wantMinor int64
+		wantCode  errors.ErrorCode
+	}{
+		{"MXN cents", "150.50", "MXN", 15050, ""},
+		{"MXN whole", "150", "MXN", 15000, ""},
+		{"MXN one decimal", "0.5", "MXN", 50, ""},
+		{"Negative", "-3.25", "MXN", -325, ""},
+		{"JPY", "1500", "JPY", 1500, ""},
+		{"KWD three decimals", "1.125", "KWD", 1125, ""},
+		{"JPY rejects decimals", "1500.5", "JPY", 0, errors.ErrInvalidAmount},
+		{"MXN rejects three decimals", "1.005", "MXN", 0, errors.ErrInvalidAmount},
+		{"Thousands separator", "1,500.00", "MXN", 0, errors.ErrInvalidAmount},
+		{"Exponent", "1e3", "MXN", 0, errors.ErrInvalidAmount},
+		{"Leading plus", "+10", "MXN", 0, errors.ErrInvalidAmount},
+		{"Spaces", " 10", "MXN", 0, errors.ErrInvalidAmount},
+		{"Empty", "", "MXN", 0, errors.ErrInvalidAmount},
+		{"Overflow", "99999999999999999999", "MXN", 0, errors.ErrInvalidAmount},
+		{"Unknown currency", "10", "ABC", 0, errors.ErrInvalidCurrency},
+		{"Currency without minor unit", "10", "XAU", 0, errors.ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Parse(tt.value, tt.currency)
+			if tt.wantCode != "" {
+				if err == nil {
+					t.Fatalf("Parse() = %v, want %v", a, tt.wantCode)
+				}
+				if err.Code() != tt.wantCode {
+					t.Errorf("Code() = %v, want %v", err.Code(), tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if a.Minor() != tt.wantMinor {
+				t.Errorf("Minor() = %d, want %d", a.Minor(), tt.wantMinor)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{MustParse("150.5", "MXN"), "150.50"},
+		{MustParse("-0.05", "MXN"), "-0.05"},
+		{MustParse("1500", "JPY"), "1500"},
+		{MustParse("1.1", "KWD"), "1.100"},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := MustParse("0.10", "MXN"), MustParse("0.20", "MXN")
+	sum, err := a.Add(b)
+	if err != nil || sum.String() != "0.30" {
+		t.Errorf("Add() = %v, %v, want 0.30", sum, err)
+	}
+	diff, err := a.Sub(b)
+	if err != nil || diff.String() != "-0.10" {
+		t.Errorf("Sub() = %v, %v, want -0.10", diff, err)
+	}
+	if cmp, _ := a.Cmp(b); cmp != -1 {
+		t.Errorf("Cmp() = %d, want -1", cmp)
+	}
+	if _, err := a.Add(MustParse("1", "JPY")); err == nil || err.Code() != errors.ErrInvalidCurrency {
+		t.Errorf("Add() across currencies error = %v, want %v", err, errors.ErrInvalidCurrency)
+	}
+	if _, err := MustParse("92233720368547758.07", "MXN").Add(MustParse("0.01", "MXN")); err == nil {
+		t.Error("Add() expected overflow error")
+	}
+}
+
+func TestMulRat(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		factor *big.Rat
+		want   string
+	}{
+		{MustParse("100", "MXN"), big.NewRat(5, 2), "250.00"},
+		{MustParse("10", "MXN"), big.NewRat(10, 3), "33.33"},
+		{MustParse("-10", "MXN"), big.NewRat(10, 3), "-33.33"},
+		{MustParse("100", "JPY"), big.NewRat(19, 10), "190"},
+	}
+	for _, tt := range tests {
+		got, err := tt.amount.MulRat(tt.factor)
+		if err != nil || got.String() != tt.want {
+			t.Errorf("%s.MulRat(%s) = %v, %v, want %s", tt.amount, tt.factor.RatString(), got, err, tt.want)
+		}
+	}
+}
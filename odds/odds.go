@@ -0,0 +1,178 @@
+package odds
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+type Format string
+
+const (
+	Decimal    Format = "decimal"    // 2.50
+	Fractional Format = "fractional" // 3/2
+	American   Format = "american"   // +150
+)
+
+var (
+	decimalRegex    = regexp.MustCompile(`^\d+(\.\d+)?$`)
+	fractionalRegex = regexp.MustCompile(`^(\d+)/(\d+)$`)
+	americanRegex   = regexp.MustCompile(`^[+-]?\d+(\.\d+)?$`)
+
+	one     = big.NewRat(1, 1)
+	two     = big.NewRat(2, 1)
+	hundred = big.NewRat(100, 1)
+)
+
+// Odds is a price stored exactly as decimal odds, the total return per unit
+// staked. The zero value is not valid; use Parse or FromDecimal.
+type Odds struct {
+	decimal *big.Rat
+}
+
+// Parse reads odds in the given format. Invalid input returns an
+// ErrInvalidFormat validation error whose details carry the format, the value
+// and the reason.
+func Parse(format Format, value string) (Odds, errors.LayerError) {
+	s := strings.TrimSpace(value)
+	switch format {
+	case Decimal:
+		if !decimalRegex.MatchString(s) {
+			return Odds{}, invalid(format, value, "malformed decimal odds")
+		}
+		r, _ := new(big.Rat).SetString(s)
+		return FromDecimal(r)
+	case Fractional:
+		if strings.EqualFold(s, "evens") || strings.EqualFold(s, "evs") {
+			s = "1/1"
+		}
+		m := fractionalRegex.FindStringSubmatch(s)
+		if m == nil {
+			return Odds{}, invalid(format, value, "malformed fractional odds")
+		}
+		num, _ := new(big.Int).SetString(m[1], 10)
+		den, _ := new(big.Int).SetString(m[2], 10)
+		if den.Sign() == 0 {
+			return Odds{}, invalid(format, value, "zero denominator")
+		}
+		if num.Sign() == 0 {
+			return Odds{}, invalid(format, value, "zero numerator")
+		}
+		return Odds{decimal: new(big.Rat).Add(one, new(big.Rat).SetFrac(num, den))}, nil
+	case American:
+		if !americanRegex.MatchString(s) {
+			return Odds{}, invalid(format, value, "malformed American odds")
+		}
+		r, _ := new(big.Rat).SetString(strings.TrimPrefix(s, "+"))
+		abs := new(big.Rat).Abs(r)
+		if abs.Cmp(hundred) < 0 {
+			return Odds{}, invalid(format, value, "American odds must be at least 100 in absolute value")
+		}
+		if r.Sign() > 0 {
+			// +A pays A per 100 staked.
+			return Odds{decimal: new(big.Rat).Add(one, new(big.Rat).Quo(r, hundred))}, nil
+		}
+		// -A needs A staked to win 100.
+		return Odds{decimal: new(big.Rat).Add(one, new(big.Rat).Quo(hundred, abs))}, nil
+	}
+	return Odds{}, errors.NewValidationError(errors.ErrInvalidFormat,
+		fmt.Sprintf("Unknown odds format '%s'", format),
+		map[string]any{"format": string(format), "value": value})
+}
+
+// FromDecimal builds odds from an exact decimal price, which must be greater
+// than 1.
+func FromDecimal(r *big.Rat) (Odds, errors.LayerError) {
+	if r == nil || r.Cmp(one) <= 0 {
+		value := "<nil>"
+		if r != nil {
+			value = r.RatString()
+		}
+		return Odds{}, invalid(Decimal, value, "decimal odds must be greater than 1.0")
+	}
+	return Odds{decimal: new(big.Rat).Set(r)}, nil
+}
+
+// MustParse is like Parse but panics on error. It is intended for constants
+// and tests.
+func MustParse(format Format, value string) Odds {
+	o, err := Parse(format, value)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
+// IsZero reports whether o is the zero value rather than a parsed price.
+func (o Odds) IsZero() bool {
+	return o.decimal == nil
+}
+
+// Decimal returns a copy of the exact decimal price.
+func (o Odds) Decimal() *big.Rat {
+	return new(big.Rat).Set(o.decimal)
+}
+
+// Fractional returns the reduced fractional price, e.g. 3 and 2 for 3/2.
+func (o Odds) Fractional() (num, den *big.Int) {
+	f := new(big.Rat).Sub(o.decimal, one)
+	return new(big.Int).Set(f.Num()), new(big.Int).Set(f.Denom())
+}
+
+// American returns the exact American price, positive for odds of 2.0 and
+// above and negative below.
+func (o Odds) American() *big.Rat {
+	profit := new(big.Rat).Sub(o.decimal, one)
+	if o.decimal.Cmp(two) >= 0 {
+		return profit.Mul(profit, hundred)
+	}
+	return new(big.Rat).Neg(new(big.Rat).Quo(hundred, profit))
+}
+
+// ImpliedProbability returns 1 / decimal odds.
+func (o Odds) ImpliedProbability() *big.Rat {
+	return new(big.Rat).Inv(o.decimal)
+}
+
+// Cmp compares the prices of o and other like big.Rat.Cmp.
+func (o Odds) Cmp(other Odds) int {
+	return o.decimal.Cmp(other.decimal)
+}
+
+// Format renders the odds in the given format. Decimal odds are rounded to
+// two places and American odds to two places when they are not whole.
+func (o Odds) Format(format Format) string {
+	switch format {
+	case Fractional:
+		num, den := o.Fractional()
+		return num.String() + "/" + den.String()
+	case American:
+		a := o.American()
+		s := ratString(a)
+		if a.Sign() > 0 {
+			s = "+" + s
+		}
+		return s
+	}
+	return o.decimal.FloatString(2)
+}
+
+func (o Odds) String() string {
+	return o.Format(Decimal)
+}
+
+func ratString(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	return r.FloatString(2)
+}
+
+func invalid(format Format, value, reason string) errors.LayerError {
+	return errors.NewValidationError(errors.ErrInvalidFormat,
+		fmt.Sprintf("Invalid %s odds '%s': %s", format, value, reason),
+		map[string]any{"format": string(format), "value": value, "reason": reason})
+}
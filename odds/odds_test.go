@@ -0,0 +1,118 @@
+package odds
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      Format
+		value       string
+		wantDecimal string
+		wantReason  string
+	}{
+		{"Decimal", Decimal, "2.50", "5/2", ""},
+		{"Decimal integer", Decimal, "3", "3", ""},
+		{"Decimal at one", Decimal, "1.0", "", "decimal odds must be greater than 1.0"},
+		{"Decimal below one", Decimal, "0.5", "", "decimal odds must be greater than 1.0"},
+		{"Decimal malformed", Decimal, "2,50", "", "malformed decimal odds"},
+		{"Decimal rejects fraction syntax", Decimal, "3/2", "", "malformed decimal odds"},
+		{"Fractional", Fractional, "3/2", "5/2", ""},
+		{"Fractional unreduced", Fractional, "10/4", "7/2", ""},
+		{"Fractional evens", Fractional, "evens", "2", ""},
+		{"Fractional zero denominator", Fractional, "3/0", "", "zero denominator"},
+		{"Fractional zero numerator", Fractional, "0/1", "", "zero numerator"},
+		{"Fractional malformed", Fractional, "3-2", "", "malformed fractional odds"},
+		{"American positive", American, "+150", "5/2", ""},
+		{"American unsigned", American, "150", "5/2", ""},
+		{"American negative", American, "-200", "3/2", ""},
+		{"American even", American, "+100", "2", ""},
+		{"American below 100", American, "+50", "", "American odds must be at least 100 in absolute value"},
+		{"American malformed", American, "+1.5.0", "", "malformed American odds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := Parse(tt.format, tt.value)
+			if tt.wantReason != "" {
+				if err == nil {
+					t.Fatalf("Parse() expected error %q", tt.wantReason)
+				}
+				if err.Code() != errors.ErrInvalidFormat {
+					t.Errorf("Code() = %v, want %v", err.Code(), errors.ErrInvalidFormat)
+				}
+				if err.Details()["reason"] != tt.wantReason || err.Details()["format"] != string(tt.format) {
+					t.Errorf("Details() = %v, want reason %q", err.Details(), tt.wantReason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if got := o.Decimal().RatString(); got != tt.wantDecimal {
+				t.Errorf("Decimal() = %s, want %s", got, tt.wantDecimal)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		decimal        string
+		wantDecimal    string
+		wantFractional string
+		wantAmerican   string
+	}{
+		{"2.5", "2.50", "3/2", "+150"},
+		{"2", "2.00", "1/1", "+100"},
+		{"1.5", "1.50", "1/2", "-200"},
+		{"1.3", "1.30", "3/10", "-333.33"},
+		{"11", "11.00", "10/1", "+1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.decimal, func(t *testing.T) {
+			o := MustParse(Decimal, tt.decimal)
+			if got := o.Format(Decimal); got != tt.wantDecimal {
+				t.Errorf("Format(Decimal) = %s, want %s", got, tt.wantDecimal)
+			}
+			if got := o.Format(Fractional); got != tt.wantFractional {
+				t.Errorf("Format(Fractional) = %s, want %s", got, tt.wantFractional)
+			}
+			if got := o.Format(American); got != tt.wantAmerican {
+				t.Errorf("Format(American) = %s, want %s", got, tt.wantAmerican)
+			}
+		})
+	}
+}
+
+func TestRoundTripIsExact(t *testing.T) {
+	for _, american := range []string{"-110", "-333", "+105", "-105", "+2500"} {
+		o := MustParse(American, american)
+		back := MustParse(Fractional, o.Format(Fractional))
+		if o.Cmp(back) != 0 {
+			t.Errorf("%s: fractional round trip = %s, want %s", american, back.Decimal().RatString(), o.Decimal().RatString())
+		}
+		if o.American().Cmp(mustRat(american)) != 0 {
+			t.Errorf("%s: American() = %s", american, o.American().RatString())
+		}
+	}
+}
+
+func TestImpliedProbability(t *testing.T) {
+	if got := MustParse(Fractional, "3/1").ImpliedProbability().RatString(); got != "1/4" {
+		t.Errorf("ImpliedProbability() = %s, want 1/4", got)
+	}
+}
+
+func mustRat(s string) *big.Rat {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		panic(s)
+	}
+	return r
+}
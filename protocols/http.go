@@ -0,0 +1,66 @@
+package protocols
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// WriteHTTPError renders err with handler as a JSON body and the mapped
+// status code. A "retry_after" detail, in seconds, sets Retry-After; rate
+// limit errors also set RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset from the "limit", "remaining" and "reset" details.
+func WriteHTTPError(w http.ResponseWriter, handler HTTPErrorHandler, err errors.LayerError) {
+	response := handler.HandleHTTPError(err)
+	details := err.Details()
+	if v, ok := seconds(details["retry_after"]); ok {
+		w.Header().Set("Retry-After", v)
+	}
+	if err.Type() == errors.RateLimitError {
+		for header, key := range map[string]string{
+			"RateLimit-Limit":     "limit",
+			"RateLimit-Remaining": "remaining",
+			"RateLimit-Reset":     "reset",
+		} {
+			if v, ok := seconds(details[key]); ok {
+				w.Header().Set(header, v)
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(response.HTTPStatus)
+	_ = json.NewEncoder(w).Encode(response.ProtocolResponse)
+}
+
+// StatusText is http.StatusText with the non-standard 499 Client Closed
+// Request that ErrRequestCanceled maps to. Other unknown statuses read
+// "Error N", so the text is never empty.
+func StatusText(status int) string {
+	if text := http.StatusText(status); text != "" {
+		return text
+	}
+	if status == 499 {
+		return "Client Closed Request"
+	}
+	return "Error " + strconv.Itoa(status)
+}
+
+// seconds formats a numeric detail as a non-negative integer, rounding
+// fractions up.
+func seconds(v any) (string, bool) {
+	var f float64
+	switch n := v.(type) {
+	case int:
+		f = float64(n)
+	case int64:
+		f = float64(n)
+	case float64:
+		f = n
+	default:
+		return "", false
+	}
+	return strconv.FormatInt(int64(math.Max(0, math.Ceil(f))), 10), true
+}
@@ -0,0 +1,156 @@
+// Package httpx holds helpers for net/http handlers that report failures as
+// LayerErrors.
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors/translate"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/validation"
+)
+
+// DefaultMaxBytes is the body limit used when Options.MaxBytes is zero.
+const DefaultMaxBytes = 1 << 20
+
+// Options configures DecodeJSON. The zero value is strict.
+type Options struct {
+	// MaxBytes limits the body size. Defaults to DefaultMaxBytes.
+	MaxBytes int64
+	// AllowUnknownFields accepts fields that dst does not declare.
+	AllowUnknownFields bool
+	// AllowMissingContentType accepts requests without a Content-Type
+	// header. A Content-Type other than JSON is always rejected.
+	AllowMissingContentType bool
+	// SkipValidation disables validation.Struct and the Validate method.
+	SkipValidation bool
+	// Profile selects the tag rules that apply, such as
+	// validation.Group("create") or validation.PartialGroup("update") for a
+	// PATCH body. Defaults to the empty profile, which runs the rules
+	// without groups.
+	Profile validation.Profile
+}
+
+// DecodeJSON decodes the body of r, which must be a single JSON object, into
+// dst and validates it. Every failure is a LayerError:
+//
+//   - UNSUPPORTED_MEDIA_TYPE (415) when Content-Type is not JSON
+//   - REQUEST_TOO_LARGE (413) when the body exceeds MaxBytes
+//   - MALFORMED_REQUEST (400) for an empty body, invalid JSON, a value that
+//     is not an object or trailing data after it
+//   - UNKNOWN_FIELD (400) for a field dst does not declare
+//   - INVALID_FORMAT (400) for a value of the wrong type, with the "field"
+//     path
+//
+// When decoding succeeds, dst is checked with Profile.StructContext, which
+// covers its `validate` tags and nested Validatable values, and then dst
+// itself is validated if it implements validation.ValidatableWithContext or
+// validation.Validatable. The request context is passed to both.
+func DecodeJSON(r *http.Request, dst any, opts Options) errors.LayerError {
+	if err := checkContentType(r.Header.Get("Content-Type"), opts.AllowMissingContentType); err != nil {
+		return err
+	}
+	body, err := readBody(r, opts.MaxBytes)
+	if err != nil {
+		return err
+	}
+	if err := decode(body, dst, opts.AllowUnknownFields); err != nil {
+		return err
+	}
+	if opts.SkipValidation {
+		return nil
+	}
+	if err := opts.Profile.StructContext(r.Context(), dst); err != nil {
+		return err
+	}
+	switch v := dst.(type) {
+	case validation.ValidatableWithContext:
+		return v.ValidateWithContext(r.Context())
+	case validation.Validatable:
+		return v.Validate()
+	}
+	return nil
+}
+
+func checkContentType(header string, allowMissing bool) errors.LayerError {
+	if header == "" && allowMissing {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+	return errors.NewValidationError(errors.ErrUnsupportedMediaType,
+		"Content-Type must be application/json",
+		map[string]any{"content_type": header})
+}
+
+func readBody(r *http.Request, maxBytes int64) ([]byte, errors.LayerError) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBytes))
+	var tooLarge *http.MaxBytesError
+	if stderrors.As(err, &tooLarge) {
+		return nil, errors.NewValidationError(errors.ErrRequestTooLarge,
+			fmt.Sprintf("Request body must not exceed %d bytes", maxBytes),
+			map[string]any{"max_bytes": maxBytes})
+	}
+	if err != nil {
+		return nil, translate.Translate(err)
+	}
+	return body, nil
+}
+
+func decode(body []byte, dst any, allowUnknown bool) errors.LayerError {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return errors.NewValidationError(errors.ErrMalformedRequest, "Request body must not be empty")
+	}
+	if trimmed[0] != '{' {
+		return errors.NewValidationError(errors.ErrMalformedRequest, "Request body must be a JSON object")
+	}
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	if !allowUnknown {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(dst); err != nil {
+		if field, ok := unknownField(err); ok {
+			return errors.NewValidationError(errors.ErrUnknownField,
+				fmt.Sprintf("Field '%s' is not allowed", field),
+				map[string]any{"field": field})
+		}
+		return translate.Translate(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.NewValidationError(errors.ErrMalformedRequest,
+			"Request body must contain a single JSON object",
+			map[string]any{"offset": dec.InputOffset()})
+	}
+	return nil
+}
+
+// unknownField extracts the field name from the error DisallowUnknownFields
+// produces, which encoding/json does not export as a type.
+func unknownField(err error) (string, bool) {
+	rest, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !ok {
+		return "", false
+	}
+	field, uerr := strconv.Unquote(rest)
+	if uerr != nil {
+		return rest, true
+	}
+	return field, true
+}
@@ -0,0 +1,133 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/validation"
+)
+
+type placeBetRequest struct {
+	MarketID string `json:"market_id" validate:"required"`
+	Stake    int64  `json:"stake" validate:"min=1"`
+	Bet      struct {
+		Odds float64 `json:"odds"`
+	} `json:"bet"`
+}
+
+func (r placeBetRequest) Validate() errors.LayerError {
+	if r.MarketID == "closed" {
+		return errors.NewBusinessRuleError(errors.ErrInvalidState, "Market is closed")
+	}
+	return nil
+}
+
+func newRequest(contentType, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/bets", strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        Options
+		wantCode    errors.ErrorCode
+		wantField   any
+	}{
+		{"Valid", "application/json", `{"market_id": "m1", "stake": 10}`, Options{}, "", nil},
+		{"Charset and suffix", "application/vnd.betmates+json; charset=utf-8", `{"market_id": "m1", "stake": 10}`, Options{}, "", nil},
+		{"Wrong content type", "text/plain", `{"market_id": "m1"}`, Options{}, errors.ErrUnsupportedMediaType, nil},
+		{"Missing content type", "", `{"market_id": "m1", "stake": 10}`, Options{}, errors.ErrUnsupportedMediaType, nil},
+		{"Missing content type allowed", "", `{"market_id": "m1", "stake": 10}`, Options{AllowMissingContentType: true}, "", nil},
+		{"Too large", "application/json", `{"market_id": "` + strings.Repeat("x", 64) + `"}`, Options{MaxBytes: 32}, errors.ErrRequestTooLarge, nil},
+		{"Empty body", "application/json", "  ", Options{}, errors.ErrMalformedRequest, nil},
+		{"Not an object", "application/json", `[{"market_id": "m1"}]`, Options{}, errors.ErrMalformedRequest, nil},
+		{"Syntax error", "application/json", `{"market_id": }`, Options{}, errors.ErrMalformedRequest, nil},
+		{"Truncated", "application/json", `{"market_id": "m1"`, Options{}, errors.ErrMalformedRequest, nil},
+		{"Trailing object", "application/json", `{"market_id": "m1", "stake": 1} {}`, Options{}, errors.ErrMalformedRequest, nil},
+		{"Trailing brace", "application/json", `{"market_id": "m1", "stake": 1}}`, Options{}, errors.ErrMalformedRequest, nil},
+		{"Unknown field", "application/json", `{"market_id": "m1", "stake": 1, "odds": 2}`, Options{}, errors.ErrUnknownField, "odds"},
+		{"Unknown field allowed", "application/json", `{"market_id": "m1", "stake": 1, "odds": 2}`, Options{AllowUnknownFields: true}, "", nil},
+		{"Wrong type", "application/json", `{"market_id": "m1", "bet": {"odds": "evens"}}`, Options{}, errors.ErrInvalidFormat, "bet.odds"},
+		{"Tag validation", "application/json", `{"market_id": "m1", "stake": 0}`, Options{}, errors.ErrInvalidFormat, "stake"},
+		{"Validate method", "application/json", `{"market_id": "closed", "stake": 1}`, Options{}, errors.ErrInvalidState, nil},
+		{"Validation skipped", "application/json", `{"market_id": "closed"}`, Options{SkipValidation: true}, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst placeBetRequest
+			err := DecodeJSON(newRequest(tt.contentType, tt.body), &dst, tt.opts)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("DecodeJSON() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("DecodeJSON() expected %s, got nil", tt.wantCode)
+			}
+			if err.Code() != tt.wantCode {
+				t.Errorf("DecodeJSON() Code() = %s, want %s (%v)", err.Code(), tt.wantCode, err)
+			}
+			if tt.wantField != nil && err.Details()["field"] != tt.wantField {
+				t.Errorf("DecodeJSON() Details[field] = %v, want %v", err.Details()["field"], tt.wantField)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON_Decodes(t *testing.T) {
+	var dst placeBetRequest
+	body := `{"market_id": "m1", "stake": 250, "bet": {"odds": 2.5}}`
+	if err := DecodeJSON(newRequest("application/json", body), &dst, Options{}); err != nil {
+		t.Fatalf("DecodeJSON() unexpected error: %v", err)
+	}
+	if dst.MarketID != "m1" || dst.Stake != 250 || dst.Bet.Odds != 2.5 {
+		t.Errorf("DecodeJSON() dst = %+v", dst)
+	}
+}
+
+type betRequest struct {
+	MarketID *string `json:"market_id" validate:"required,groups=create"`
+	Stake    *int64  `json:"stake" validate:"required,min=1"`
+}
+
+func TestDecodeJSON_Profile(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		profile   validation.Profile
+		wantCode  errors.ErrorCode
+		wantField any
+	}{
+		{"Create requires grouped field", `{"stake": 10}`, validation.Group("create"), errors.ErrMissingRequired, "market_id"},
+		{"Grouped field skipped", `{"stake": 10}`, validation.Profile{}, "", nil},
+		{"Partial skips absent fields", `{"market_id": "m1"}`, validation.PartialGroup("create"), "", nil},
+		{"Partial validates present fields", `{"stake": 0}`, validation.PartialGroup("create"), errors.ErrInvalidFormat, "stake"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst betRequest
+			err := DecodeJSON(newRequest("application/json", tt.body), &dst, Options{Profile: tt.profile})
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("DecodeJSON() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Code() != tt.wantCode || err.Details()["field"] != tt.wantField {
+				t.Errorf("DecodeJSON() = %v, want %s on %v", err, tt.wantCode, tt.wantField)
+			}
+		})
+	}
+}
@@ -60,6 +60,8 @@ func (h *DefaultHTTPErrorHandler) getFallbackHTTPStatus(errType errors.ErrorType
 		return http.StatusConflict
 	case errors.BusinessRuleError:
 		return http.StatusUnprocessableEntity
+	case errors.RateLimitError:
+		return http.StatusTooManyRequests
 	case errors.InfrastructureError:
 		return http.StatusFailedDependency
 	case errors.InternalError:
@@ -72,15 +74,37 @@ func (h *DefaultHTTPErrorHandler) getFallbackHTTPStatus(errType errors.ErrorType
 func getDefaultErrorMapping() map[errors.ErrorCode]int {
 	return map[errors.ErrorCode]int{
 		// Validation Errors (400)
-		errors.ErrInvalidEmail:    http.StatusBadRequest,
-		errors.ErrInvalidPassword: http.StatusBadRequest,
-		errors.ErrMissingRequired: http.StatusBadRequest,
-		errors.ErrInvalidFormat:   http.StatusBadRequest,
+		errors.ErrInvalidEmail:     http.StatusBadRequest,
+		errors.ErrInvalidPassword:  http.StatusBadRequest,
+		errors.ErrMissingRequired:  http.StatusBadRequest,
+		errors.ErrInvalidFormat:    http.StatusBadRequest,
+		errors.ErrInvalidDate:      http.StatusBadRequest,
+		errors.ErrUnderage:         http.StatusBadRequest,
+		errors.ErrInvalidUUID:      http.StatusBadRequest,
+		errors.ErrInvalidURL:       http.StatusBadRequest,
+		errors.ErrInvalidPhone:     http.StatusBadRequest,
+		errors.ErrInvalidCountry:   http.StatusBadRequest,
+		errors.ErrInvalidCurrency:  http.StatusBadRequest,
+		errors.ErrInvalidIP:        http.StatusBadRequest,
+		errors.ErrInvalidCIDR:      http.StatusBadRequest,
+		errors.ErrInvalidSlug:      http.StatusBadRequest,
+		errors.ErrInvalidBase64:    http.StatusBadRequest,
+		errors.ErrInvalidHex:       http.StatusBadRequest,
+		errors.ErrInvalidAmount:    http.StatusBadRequest,
+		errors.ErrMalformedRequest: http.StatusBadRequest,
+		errors.ErrUnknownField:     http.StatusBadRequest,
+
+		// Request Body Errors (413, 415)
+		errors.ErrRequestTooLarge:      http.StatusRequestEntityTooLarge,
+		errors.ErrUnsupportedMediaType: http.StatusUnsupportedMediaType,
 
 		// Authentication Errors (401)
 		errors.ErrInvalidToken:       http.StatusUnauthorized,
 		errors.ErrExpiredToken:       http.StatusUnauthorized,
 		errors.ErrInvalidCredentials: http.StatusUnauthorized,
+		errors.ErrTokenNotYetValid:   http.StatusUnauthorized,
+		errors.ErrInvalidIssuer:      http.StatusUnauthorized,
+		errors.ErrInvalidAudience:    http.StatusUnauthorized,
 
 		// Authorization Errors (403)
 		errors.ErrInsufficientPermissions: http.StatusForbidden,
@@ -91,17 +115,38 @@ func getDefaultErrorMapping() map[errors.ErrorCode]int {
 		errors.ErrResourceNotFound: http.StatusNotFound,
 
 		// Conflict Errors (409)
-		errors.ErrUserAlreadyExists: http.StatusConflict,
-		errors.ErrEmailAlreadyTaken: http.StatusConflict,
+		errors.ErrUserAlreadyExists:       http.StatusConflict,
+		errors.ErrEmailAlreadyTaken:       http.StatusConflict,
+		errors.ErrDuplicateIdempotencyKey: http.StatusConflict,
+		errors.ErrAccountAlreadyExists:    http.StatusConflict,
+		errors.ErrIdempotencyKeyReused:    http.StatusConflict,
+		errors.ErrRequestInProgress:       http.StatusConflict,
 
 		// Business Rule Errors (422)
 		errors.ErrInvalidBusinessRule: http.StatusUnprocessableEntity,
 		errors.ErrInvalidState:        http.StatusUnprocessableEntity,
+		errors.ErrStakeOutOfRange:     http.StatusUnprocessableEntity,
+		errors.ErrLimitExceeded:       http.StatusUnprocessableEntity,
+		errors.ErrInvalidSettlement:   http.StatusUnprocessableEntity,
+		errors.ErrInsufficientFunds:   http.StatusUnprocessableEntity,
+
+		// Rate Limit Errors (429)
+		errors.ErrRateLimited: http.StatusTooManyRequests,
 
 		// Infrastructure Errors (424)
 		errors.ErrDatabaseConnection:  http.StatusFailedDependency,
 		errors.ErrExternalService:     http.StatusFailedDependency,
 		errors.ErrRepositoryOperation: http.StatusFailedDependency,
+
+		// Circuit Breaker Errors (503)
+		errors.ErrCircuitOpen: http.StatusServiceUnavailable,
+
+		// Timeout Errors
+		errors.ErrTimeout:         http.StatusGatewayTimeout,
+		errors.ErrRequestCanceled: 499, // Client Closed Request, as logged by nginx
+
+		// Internal Errors (500)
+		errors.ErrInternal: http.StatusInternalServerError,
 	}
 }
 
@@ -148,6 +193,8 @@ func (h *DefaultGRPCErrorHandler) mapToGRPCCode(errType errors.ErrorType) int {
 		return 6 // AlreadyExists
 	case errors.BusinessRuleError:
 		return 3 // InvalidArgument
+	case errors.RateLimitError:
+		return 8 // ResourceExhausted
 	case errors.InfrastructureError:
 		return 14 // Unavailable
 	case errors.InternalError:
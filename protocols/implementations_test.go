@@ -1,7 +1,9 @@
 package protocols
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
@@ -50,6 +52,11 @@ func TestDefaultHTTPErrorHandler_HandleHTTPError(t *testing.T) {
 			err:        errors.NewInfrastructureError(errors.ErrDatabaseConnection, "Database connection failed"),
 			wantStatus: http.StatusFailedDependency,
 		},
+		{
+			name:       "Circuit Open Error should return 503",
+			err:        errors.NewInfrastructureError(errors.ErrCircuitOpen, "Circuit 'odds' is open"),
+			wantStatus: http.StatusServiceUnavailable,
+		},
 	}
 
 	for _, tt := range tests {
@@ -162,6 +169,12 @@ func TestDefaultGRPCErrorHandler_HandleGRPCError(t *testing.T) {
 			wantCode: 14, // Unavailable
 			wantMsg:  "Database connection failed",
 		},
+		{
+			name:     "Rate Limit Error should return ResourceExhausted (8)",
+			err:      errors.NewRateLimitError(errors.ErrRateLimited, "Rate limit exceeded"),
+			wantCode: 8, // ResourceExhausted
+			wantMsg:  "Rate limit exceeded",
+		},
 	}
 
 	for _, tt := range tests {
@@ -225,3 +238,87 @@ func TestDefaultGRPCErrorHandler_HandleError(t *testing.T) {
 		t.Errorf("HandleError() Details[field] = %v, want %v", response.Details["field"], "email")
 	}
 }
+
+func TestWriteHTTPError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := errors.NewConflictError(errors.ErrUserAlreadyExists, "User already exists", map[string]interface{}{"user_id": "42"})
+
+	WriteHTTPError(rec, NewDefaultHTTPErrorHandler(), err)
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var body ProtocolResponse
+	if decodeErr := json.NewDecoder(rec.Body).Decode(&body); decodeErr != nil {
+		t.Fatalf("decode body: %v", decodeErr)
+	}
+	if body.Code != string(errors.ErrUserAlreadyExists) || body.Details["user_id"] != "42" {
+		t.Errorf("body = %+v, want code and details", body)
+	}
+}
+
+func TestStatusText(t *testing.T) {
+	for status, want := range map[int]string{404: "Not Found", 499: "Client Closed Request", 599: "Error 599"} {
+		if got := StatusText(status); got != want {
+			t.Errorf("StatusText(%d) = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestWriteHTTPError_RateLimitHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         errors.LayerError
+		wantStatus  int
+		wantHeaders map[string]string
+	}{
+		{
+			name: "Rate limit error sets Retry-After and RateLimit headers",
+			err: errors.NewRateLimitError(errors.ErrRateLimited, "Rate limit exceeded",
+				map[string]interface{}{"retry_after": 1.2, "limit": 10, "remaining": 0, "reset": int64(30)}),
+			wantStatus:  http.StatusTooManyRequests,
+			wantHeaders: map[string]string{"Retry-After": "2", "RateLimit-Limit": "10", "RateLimit-Remaining": "0", "RateLimit-Reset": "30"},
+		},
+		{
+			name: "Other errors only set Retry-After",
+			err: errors.NewInfrastructureError(errors.ErrExternalService, "Service unavailable",
+				map[string]interface{}{"retry_after": 5, "limit": 10}),
+			wantStatus:  http.StatusFailedDependency,
+			wantHeaders: map[string]string{"Retry-After": "5", "RateLimit-Limit": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			WriteHTTPError(rec, NewDefaultHTTPErrorHandler(), tt.err)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			for header, want := range tt.wantHeaders {
+				if got := rec.Header().Get(header); got != want {
+					t.Errorf("%s = %q, want %q", header, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultErrorMapping_MatchesCatalog(t *testing.T) {
+	mapping := getDefaultErrorMapping()
+	catalog := map[errors.ErrorCode]bool{}
+	for _, d := range errors.Catalog() {
+		catalog[d.Code] = true
+		if _, ok := mapping[d.Code]; !ok {
+			t.Errorf("%s is in the catalog but has no HTTP status", d.Code)
+		}
+	}
+	for code := range mapping {
+		if !catalog[code] {
+			t.Errorf("%s has an HTTP status but no catalog definition", code)
+		}
+	}
+}
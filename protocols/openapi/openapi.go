@@ -0,0 +1,207 @@
+// Package openapi generates OpenAPI 3.1 components describing the error
+// responses of an HTTPErrorHandler, so API specs reference them instead of
+// hand-writing error schemas.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/protocols"
+)
+
+// Version is the OpenAPI version of generated documents.
+const Version = "3.1.0"
+
+// ErrorResponseSchema names the schema of protocols.ProtocolResponse.
+const ErrorResponseSchema = "ErrorResponse"
+
+// Document is a standalone OpenAPI document holding only components. Specs
+// include its responses by reference, e.g.
+// "errors.json#/components/responses/Error404".
+type Document struct {
+	OpenAPI    string     `json:"openapi"`
+	Info       Info       `json:"info"`
+	Components Components `json:"components"`
+}
+
+// Info is the info object of a Document.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Components holds the generated reusable responses and schemas.
+type Components struct {
+	Responses map[string]*Response `json:"responses,omitempty"`
+	Schemas   map[string]*Schema   `json:"schemas,omitempty"`
+}
+
+// Response is an OpenAPI response object.
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType is an OpenAPI media type object.
+type MediaType struct {
+	Schema   *Schema             `json:"schema,omitempty"`
+	Examples map[string]*Example `json:"examples,omitempty"`
+}
+
+// Example is an OpenAPI example object.
+type Example struct {
+	Summary     string `json:"summary,omitempty"`
+	Description string `json:"description,omitempty"`
+	Value       any    `json:"value"`
+}
+
+// Header is an OpenAPI header object.
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// Schema is the subset of the OpenAPI 3.1 schema object used by error
+// responses.
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	AllOf       []*Schema          `json:"allOf,omitempty"`
+}
+
+// Name is the component name of the response and schema for an HTTP status,
+// such as "Error404".
+func Name(status int) string {
+	return fmt.Sprintf("Error%d", status)
+}
+
+// ResponseRef is the local reference to the response for an HTTP status.
+func ResponseRef(status int) string {
+	return "#/components/responses/" + Name(status)
+}
+
+// Generate renders every definition with handler and groups them by HTTP
+// status. Each status gets a response and a schema named by Name: the
+// schema narrows ErrorResponse to the codes and types of the status, and the
+// response has one example per code, built from Definition.Example. Statuses
+// with a "retry_after" detail declare Retry-After, and rate limit errors the
+// RateLimit-* headers, as protocols.WriteHTTPError sets them.
+func Generate(handler protocols.HTTPErrorHandler, defs []errors.Definition) Components {
+	byStatus := map[int][]errors.Definition{}
+	for _, d := range defs {
+		status := handler.HandleHTTPError(d.Example()).HTTPStatus
+		byStatus[status] = append(byStatus[status], d)
+	}
+
+	c := Components{
+		Responses: map[string]*Response{},
+		Schemas:   map[string]*Schema{ErrorResponseSchema: errorResponseSchema()},
+	}
+	for status, defs := range byStatus {
+		sort.Slice(defs, func(i, j int) bool { return defs[i].Code < defs[j].Code })
+		c.Schemas[Name(status)] = statusSchema(defs)
+		c.Responses[Name(status)] = response(handler, status, defs)
+	}
+	return c
+}
+
+// NewDocument wraps the components generated from the catalog for handler
+// in a Document.
+func NewDocument(title, version string, handler protocols.HTTPErrorHandler) Document {
+	return Document{
+		OpenAPI:    Version,
+		Info:       Info{Title: title, Version: version},
+		Components: Generate(handler, errors.Catalog()),
+	}
+}
+
+// JSON encodes the document with indentation.
+func (d Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// errorResponseSchema mirrors protocols.ProtocolResponse.
+func errorResponseSchema() *Schema {
+	return &Schema{
+		Type:        "object",
+		Description: "Error body written for every failed request.",
+		Properties: map[string]*Schema{
+			"error":   {Type: "string", Description: "Human-readable message."},
+			"code":    {Type: "string", Description: "Stable error code."},
+			"type":    {Type: "string", Description: "Error category."},
+			"details": {Type: "object", Description: "Code-specific context, such as the invalid field."},
+		},
+		Required: []string{"error"},
+	}
+}
+
+func statusSchema(defs []errors.Definition) *Schema {
+	var codes, types []string
+	seen := map[errors.ErrorType]bool{}
+	for _, d := range defs {
+		codes = append(codes, string(d.Code))
+		if !seen[d.Type] {
+			seen[d.Type] = true
+			types = append(types, string(d.Type))
+		}
+	}
+	sort.Strings(types)
+	return &Schema{AllOf: []*Schema{
+		{Ref: "#/components/schemas/" + ErrorResponseSchema},
+		{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"code": {Enum: codes},
+				"type": {Enum: types},
+			},
+			Required: []string{"code", "type"},
+		},
+	}}
+}
+
+func response(handler protocols.HTTPErrorHandler, status int, defs []errors.Definition) *Response {
+	examples := map[string]*Example{}
+	headers := map[string]*Header{}
+	zero := 0.0
+	for _, d := range defs {
+		err := d.Example()
+		examples[string(d.Code)] = &Example{
+			Summary:     string(d.Code),
+			Description: d.Description,
+			Value:       handler.HandleHTTPError(err).ProtocolResponse,
+		}
+		if _, ok := err.Details()["retry_after"]; ok {
+			headers["Retry-After"] = &Header{
+				Description: "Seconds to wait before retrying.",
+				Schema:      &Schema{Type: "integer", Minimum: &zero},
+			}
+		}
+		if err.Type() == errors.RateLimitError {
+			headers["RateLimit-Limit"] = &Header{Description: "Requests allowed in the window.", Schema: &Schema{Type: "integer", Minimum: &zero}}
+			headers["RateLimit-Remaining"] = &Header{Description: "Requests left in the window.", Schema: &Schema{Type: "integer", Minimum: &zero}}
+			headers["RateLimit-Reset"] = &Header{Description: "Seconds until the window resets.", Schema: &Schema{Type: "integer", Minimum: &zero}}
+		}
+	}
+	r := &Response{
+		Description: protocols.StatusText(status),
+		Content: map[string]*MediaType{
+			"application/json": {
+				Schema:   &Schema{Ref: "#/components/schemas/" + Name(status)},
+				Examples: examples,
+			},
+		},
+	}
+	if len(headers) > 0 {
+		r.Headers = headers
+	}
+	return r
+}
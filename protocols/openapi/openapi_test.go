@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/protocols"
+)
+
+func TestGenerate(t *testing.T) {
+	c := Generate(protocols.NewDefaultHTTPErrorHandler(), errors.Catalog())
+
+	tests := []struct {
+		name        string
+		status      int
+		code        errors.ErrorCode
+		description string
+		headers     []string
+	}{
+		{"Validation", http.StatusBadRequest, errors.ErrInvalidEmail, "Bad Request", nil},
+		{"Not found", http.StatusNotFound, errors.ErrUserNotFound, "Not Found", nil},
+		{"Rate limit", http.StatusTooManyRequests, errors.ErrRateLimited, "Too Many Requests",
+			[]string{"Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset"}},
+		{"Circuit open", http.StatusServiceUnavailable, errors.ErrCircuitOpen, "Service Unavailable", []string{"Retry-After"}},
+		{"Non-standard status", 499, errors.ErrRequestCanceled, "Client Closed Request", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := c.Responses[Name(tt.status)]
+			if resp == nil {
+				t.Fatalf("no response for %d", tt.status)
+			}
+			if resp.Description != tt.description {
+				t.Errorf("Description = %q, want %q", resp.Description, tt.description)
+			}
+			media := resp.Content["application/json"]
+			if media.Schema.Ref != "#/components/schemas/"+Name(tt.status) {
+				t.Errorf("schema $ref = %q", media.Schema.Ref)
+			}
+			example := media.Examples[string(tt.code)]
+			if example == nil {
+				t.Fatalf("no example for %s", tt.code)
+			}
+			value := example.Value.(protocols.ProtocolResponse)
+			if value.Code != string(tt.code) || value.Error == "" {
+				t.Errorf("example = %+v, want a %s body", value, tt.code)
+			}
+			if len(resp.Headers) != len(tt.headers) {
+				t.Errorf("headers = %v, want %v", resp.Headers, tt.headers)
+			}
+			for _, h := range tt.headers {
+				if resp.Headers[h] == nil {
+					t.Errorf("missing header %s", h)
+				}
+			}
+			codes := c.Schemas[Name(tt.status)].AllOf[1].Properties["code"].Enum
+			if !contains(codes, string(tt.code)) {
+				t.Errorf("code enum %v does not contain %s", codes, tt.code)
+			}
+		})
+	}
+}
+
+func TestGenerate_CustomMapping(t *testing.T) {
+	handler := protocols.NewCustomHTTPErrorHandler(map[errors.ErrorCode]int{
+		errors.ErrInvalidEmail: http.StatusUnprocessableEntity,
+	})
+	defs := []errors.Definition{mustLookup(t, errors.ErrInvalidEmail), mustLookup(t, errors.ErrMissingRequired)}
+	c := Generate(handler, defs)
+
+	if got := c.Schemas[Name(http.StatusUnprocessableEntity)].AllOf[1].Properties["code"].Enum; len(got) != 1 || got[0] != string(errors.ErrInvalidEmail) {
+		t.Errorf("422 codes = %v, want [INVALID_EMAIL]", got)
+	}
+	// Unmapped codes use the fallback status of their type.
+	if got := c.Schemas[Name(http.StatusBadRequest)].AllOf[1].Properties["code"].Enum; len(got) != 1 || got[0] != string(errors.ErrMissingRequired) {
+		t.Errorf("400 codes = %v, want [MISSING_REQUIRED]", got)
+	}
+	if len(c.Responses) != 2 || len(c.Schemas) != 3 {
+		t.Errorf("got %d responses and %d schemas, want 2 and 3", len(c.Responses), len(c.Schemas))
+	}
+}
+
+func TestNewDocument_JSON(t *testing.T) {
+	data, err := NewDocument("Betmates errors", "1.0.0", protocols.NewDefaultHTTPErrorHandler()).JSON()
+	if err != nil {
+		t.Fatalf("JSON() error: %v", err)
+	}
+	var doc struct {
+		OpenAPI    string `json:"openapi"`
+		Components struct {
+			Responses map[string]json.RawMessage `json:"responses"`
+			Schemas   map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	if doc.OpenAPI != Version {
+		t.Errorf("openapi = %q, want %q", doc.OpenAPI, Version)
+	}
+	if _, ok := doc.Components.Schemas[ErrorResponseSchema]; !ok {
+		t.Errorf("missing %s schema", ErrorResponseSchema)
+	}
+	for name := range doc.Components.Responses {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("response %s has no schema", name)
+		}
+	}
+}
+
+func mustLookup(t *testing.T, code errors.ErrorCode) errors.Definition {
+	t.Helper()
+	d, ok := errors.Lookup(code)
+	if !ok {
+		t.Fatalf("%s is not in the catalog", code)
+	}
+	return d
+}
+
+func contains(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
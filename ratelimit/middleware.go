@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/auth"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/protocols"
+)
+
+// KeyFunc derives the rate limit key of a request. An empty key skips
+// limiting.
+type KeyFunc func(r *http.Request) string
+
+// ByIP keys requests by the remote address. Behind a proxy, wrap the
+// handler with one that rewrites RemoteAddr from a trusted header first.
+func ByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ByRoute keys requests by method and path.
+func ByRoute(r *http.Request) string {
+	return r.Method + " " + r.URL.Path
+}
+
+// ByUser keys requests by the subject of the claims stored by
+// auth.Middleware, and is empty for anonymous requests.
+func ByUser(r *http.Request) string {
+	claims, ok := auth.ClaimsFromContext(r.Context())
+	if !ok {
+		return ""
+	}
+	return claims.Subject
+}
+
+// Compose joins the keys of several functions, e.g. Compose(ByUser, ByRoute)
+// for a per-user limit on each route. It is empty if any part is empty.
+func Compose(fns ...KeyFunc) KeyFunc {
+	return func(r *http.Request) string {
+		parts := make([]string, 0, len(fns))
+		for _, fn := range fns {
+			part := fn(r)
+			if part == "" {
+				return ""
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, "|")
+	}
+}
+
+// Options configures Middleware.
+type Options struct {
+	// Handler renders rejections and store failures. Defaults to
+	// protocols.NewDefaultHTTPErrorHandler().
+	Handler protocols.HTTPErrorHandler
+	// OnLimited is called with the key and result of every rejected request,
+	// before the response is written, so that callers can log or count
+	// rejections; the response does not name the key.
+	OnLimited func(r *http.Request, key string, res Result)
+}
+
+// Middleware limits requests by key. Allowed responses carry the
+// RateLimit-* headers; rejected requests are written with
+// protocols.WriteHTTPError, which also sets Retry-After.
+func Middleware(l Limiter, key KeyFunc, opts Options) func(http.Handler) http.Handler {
+	if opts.Handler == nil {
+		opts.Handler = protocols.NewDefaultHTTPErrorHandler()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k := key(r)
+			if k == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			res, err := l.Allow(r.Context(), k)
+			if err != nil {
+				if err.Type() == errors.RateLimitError && opts.OnLimited != nil {
+					opts.OnLimited(r, k, res)
+				}
+				protocols.WriteHTTPError(w, opts.Handler, err)
+				return
+			}
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.FormatInt(int64(math.Ceil(res.Reset.Seconds())), 10))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// Result describes the limit state of a key after a request.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until a request would be allowed; zero when
+	// allowed.
+	RetryAfter time.Duration
+	// Reset is how long until the limit is fully restored.
+	Reset time.Duration
+}
+
+// Limiter decides whether a request for key is allowed. When it is not,
+// Allow returns the Result and a RateLimitError with code ErrRateLimited;
+// store failures return an infrastructure error.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, errors.LayerError)
+}
+
+// TokenBucket allows bursts of up to burst requests and refills rate tokens
+// every per, so the sustained rate is rate/per.
+type TokenBucket struct {
+	store Store
+	clock clock.Clock
+	burst int
+	rate  float64 // tokens per second
+}
+
+func NewTokenBucket(store Store, c clock.Clock, rate int, per time.Duration, burst int) *TokenBucket {
+	return &TokenBucket{store: store, clock: c, burst: burst, rate: float64(rate) / per.Seconds()}
+}
+
+func (b *TokenBucket) Allow(ctx context.Context, key string) (Result, errors.LayerError) {
+	now := b.clock.Now()
+	var res Result
+	capacity := float64(b.burst)
+	ttl := seconds(capacity / b.rate)
+	err := b.store.Update(ctx, "tb:"+key, ttl, func(s State, found bool) State {
+		if !found {
+			s = State{Tokens: capacity, Stamp: now}
+		}
+		elapsed := now.Sub(s.Stamp).Seconds()
+		if elapsed > 0 {
+			s.Tokens = math.Min(capacity, s.Tokens+elapsed*b.rate)
+			s.Stamp = now
+		}
+		res = Result{Limit: b.burst}
+		if s.Tokens >= 1 {
+			s.Tokens--
+			res.Allowed = true
+		} else {
+			res.RetryAfter = seconds((1 - s.Tokens) / b.rate)
+		}
+		res.Remaining = int(s.Tokens)
+		res.Reset = seconds((capacity - s.Tokens) / b.rate)
+		return s
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return res, denied(res)
+}
+
+// SlidingWindow allows Limit requests per Window. It weights the previous
+// window's count by its overlap with the sliding window, which bounds the
+// error to a fraction of one window without storing timestamps.
+type SlidingWindow struct {
+	store  Store
+	clock  clock.Clock
+	limit  int
+	window time.Duration
+}
+
+func NewSlidingWindow(store Store, c clock.Clock, limit int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{store: store, clock: c, limit: limit, window: window}
+}
+
+func (w *SlidingWindow) Allow(ctx context.Context, key string) (Result, errors.LayerError) {
+	now := w.clock.Now()
+	var res Result
+	err := w.store.Update(ctx, "sw:"+key, 2*w.window, func(s State, found bool) State {
+		start := now.Truncate(w.window)
+		switch {
+		case !found || now.Sub(s.Stamp) >= 2*w.window:
+			s = State{Stamp: start}
+		case now.Sub(s.Stamp) >= w.window:
+			s = State{PrevCount: s.Count, Stamp: start}
+		}
+		elapsed := now.Sub(s.Stamp)
+		overlap := 1 - float64(elapsed)/float64(w.window)
+		weighted := float64(s.PrevCount)*overlap + float64(s.Count)
+
+		res = Result{Limit: w.limit, Reset: w.window - elapsed}
+		if weighted+1 <= float64(w.limit) {
+			s.Count++
+			weighted++
+			res.Allowed = true
+		} else {
+			res.RetryAfter = w.retryAfter(s, elapsed)
+		}
+		res.Remaining = max(0, w.limit-int(math.Ceil(weighted)))
+		return s
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return res, denied(res)
+}
+
+// retryAfter solves for the time at which the weighted count leaves room for
+// one more request.
+func (w *SlidingWindow) retryAfter(s State, elapsed time.Duration) time.Duration {
+	limit := float64(w.limit)
+	if float64(s.Count)+1 > limit {
+		// Wait for the next window, where the current count becomes the
+		// previous one and decays.
+		next := float64(w.window) * (1 - (limit-1)/float64(s.Count))
+		return w.window - elapsed + time.Duration(math.Max(0, next))
+	}
+	t := float64(w.window)*(1-(limit-float64(s.Count)-1)/float64(s.PrevCount)) - float64(elapsed)
+	return time.Duration(math.Max(0, t))
+}
+
+// denied leaves the key, a client IP or user ID, out of the details, since
+// they are sent to the client.
+func denied(res Result) errors.LayerError {
+	if res.Allowed {
+		return nil
+	}
+	retry := int64(math.Ceil(res.RetryAfter.Seconds()))
+	return errors.NewRateLimitError(errors.ErrRateLimited,
+		fmt.Sprintf("Rate limit exceeded, retry in %d seconds", retry),
+		map[string]any{
+			"limit":       res.Limit,
+			"remaining":   res.Remaining,
+			"retry_after": retry,
+			"reset":       int64(math.Ceil(res.Reset.Seconds())),
+		})
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,204 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+var start = time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+type step struct {
+	advance     time.Duration
+	wantAllowed bool
+	wantRetry   time.Duration
+}
+
+func runSteps(t *testing.T, fake *clock.Fake, l Limiter, steps []step) {
+	t.Helper()
+	for i, s := range steps {
+		fake.Advance(s.advance)
+		res, err := l.Allow(context.Background(), "user-1")
+		if res.Allowed != s.wantAllowed {
+			t.Fatalf("step %d: Allowed = %v, want %v", i, res.Allowed, s.wantAllowed)
+		}
+		if s.wantAllowed {
+			if err != nil {
+				t.Fatalf("step %d: unexpected error: %v", i, err)
+			}
+			continue
+		}
+		if err == nil || err.Code() != errors.ErrRateLimited || err.Type() != errors.RateLimitError {
+			t.Fatalf("step %d: error = %v, want %s", i, err, errors.ErrRateLimited)
+		}
+		if res.RetryAfter != s.wantRetry {
+			t.Errorf("step %d: RetryAfter = %v, want %v", i, res.RetryAfter, s.wantRetry)
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"Burst then reject", []step{
+			{0, true, 0}, {0, true, 0}, {0, true, 0},
+			{0, false, time.Second},
+		}},
+		{"Refills one token per second", []step{
+			{0, true, 0}, {0, true, 0}, {0, true, 0},
+			{500 * time.Millisecond, false, 500 * time.Millisecond},
+			{500 * time.Millisecond, true, 0},
+			{0, false, time.Second},
+		}},
+		{"Refill is capped at burst", []step{
+			{time.Hour, true, 0}, {0, true, 0}, {0, true, 0},
+			{0, false, time.Second},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := clock.NewFake(start)
+			runSteps(t, fake, NewTokenBucket(NewMemoryStore(fake), fake, 1, time.Second, 3), tt.steps)
+		})
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"Limit within window", []step{
+			{0, true, 0}, {0, true, 0},
+			// The two requests must decay to one in the next window.
+			{0, false, 90 * time.Second},
+		}},
+		{"Previous window decays", []step{
+			{0, true, 0}, {0, true, 0},
+			// 30s into the next window the previous two requests weigh 1.
+			{90 * time.Second, true, 0},
+			{0, false, 30 * time.Second},
+		}},
+		{"Idle for two windows resets", []step{
+			{0, true, 0}, {0, true, 0},
+			{2 * time.Minute, true, 0}, {0, true, 0},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := clock.NewFake(start)
+			runSteps(t, fake, NewSlidingWindow(NewMemoryStore(fake), fake, 2, time.Minute), tt.steps)
+		})
+	}
+}
+
+func TestLimiter_Details(t *testing.T) {
+	fake := clock.NewFake(start)
+	l := NewTokenBucket(NewMemoryStore(fake), fake, 10, time.Minute, 1)
+	_, _ = l.Allow(context.Background(), "k")
+	_, err := l.Allow(context.Background(), "k")
+	if err == nil {
+		t.Fatal("Expected rate limit error")
+	}
+	if _, ok := err.Details()["key"]; ok {
+		t.Error("Details must not expose the key")
+	}
+	want := map[string]any{"limit": 1, "remaining": 0, "retry_after": int64(6), "reset": int64(6)}
+	for k, v := range want {
+		if err.Details()[k] != v {
+			t.Errorf("Details[%s] = %v (%T), want %v", k, err.Details()[k], err.Details()[k], v)
+		}
+	}
+}
+
+func TestMemoryStore_Concurrent(t *testing.T) {
+	fake := clock.NewFake(start)
+	l := NewTokenBucket(NewMemoryStore(fake), fake, 1, time.Hour, 50)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if res, _ := l.Allow(context.Background(), "shared"); res.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != 50 {
+		t.Errorf("allowed = %d, want 50", allowed)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	fake := clock.NewFake(start)
+	l := NewSlidingWindow(NewMemoryStore(fake), fake, 1, time.Minute)
+	var limited []string
+	h := Middleware(l, Compose(ByIP, ByRoute), Options{
+		OnLimited: func(r *http.Request, key string, res Result) { limited = append(limited, key) },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	do := func(addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/bets", nil)
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := do("10.0.0.1:1234")
+	if first.Code != http.StatusOK || first.Header().Get("RateLimit-Remaining") != "0" {
+		t.Errorf("First response = %d remaining %q, want 200 remaining 0", first.Code, first.Header().Get("RateLimit-Remaining"))
+	}
+	second := do("10.0.0.1:5678")
+	if second.Code != http.StatusTooManyRequests {
+		t.Errorf("Second status = %d, want %d", second.Code, http.StatusTooManyRequests)
+	}
+	if second.Header().Get("Retry-After") != "120" || second.Header().Get("RateLimit-Limit") != "1" {
+		t.Errorf("Second headers = %v, want Retry-After 120 and RateLimit-Limit 1", second.Header())
+	}
+	if other := do("10.0.0.2:1234"); other.Code != http.StatusOK {
+		t.Errorf("Other IP status = %d, want %d", other.Code, http.StatusOK)
+	}
+	if len(limited) != 1 || limited[0] != "10.0.0.1|POST /bets" {
+		t.Errorf("OnLimited keys = %v, want [10.0.0.1|POST /bets]", limited)
+	}
+}
+
+// TestCatalogExamples keeps the catalog example, which feeds the OpenAPI and
+// error docs, in step with what the limiters return.
+func TestCatalogExamples(t *testing.T) {
+	err := denied(Result{Limit: 100, RetryAfter: 30 * time.Second, Reset: 30 * time.Second})
+	def, ok := errors.Lookup(err.Code())
+	if !ok {
+		t.Fatalf("%s is not in the catalog", err.Code())
+	}
+	want := def.Example()
+	if err.Error() != want.Error() || err.Type() != want.Type() || err.Layer() != want.Layer() {
+		t.Errorf("%s = %q (%s, %s), catalog example = %q (%s, %s)", err.Code(),
+			err.Error(), err.Type(), err.Layer(), want.Error(), want.Type(), want.Layer())
+	}
+	for k := range want.Details() {
+		if _, ok := err.Details()[k]; !ok {
+			t.Errorf("Details[%s] is in the catalog example but not emitted", k)
+		}
+	}
+	if len(err.Details()) != len(want.Details()) {
+		t.Errorf("Details = %v, catalog example = %v", err.Details(), want.Details())
+	}
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// State is what a limiter keeps per key. Token buckets use Tokens and Stamp
+// (last refill); sliding windows use Count, PrevCount and Stamp (start of the
+// current window).
+type State struct {
+	Tokens    float64
+	Count     int64
+	PrevCount int64
+	Stamp     time.Time
+}
+
+// Store keeps limiter state. Update must be atomic per key: it reads the
+// state of key, passes it to fn with found == false when absent or expired,
+// and saves the state fn returns with the given ttl. A Redis store implements
+// it with a Lua script or WATCH/MULTI.
+type Store interface {
+	Update(ctx context.Context, key string, ttl time.Duration, fn func(state State, found bool) State) errors.LayerError
+}
+
+// sweepEvery is the number of updates between removals of expired keys.
+const sweepEvery = 1024
+
+// MemoryStore is an in-process Store. It is safe for concurrent use and drops
+// expired keys periodically.
+type MemoryStore struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	entries map[string]memoryEntry
+	updates int
+}
+
+type memoryEntry struct {
+	state     State
+	expiresAt time.Time
+}
+
+func NewMemoryStore(c clock.Clock) *MemoryStore {
+	return &MemoryStore{clock: c, entries: make(map[string]memoryEntry)}
+}
+
+func (s *MemoryStore) Update(ctx context.Context, key string, ttl time.Duration, fn func(State, bool) State) errors.LayerError {
+	if err := ctx.Err(); err != nil {
+		return errors.NewInfrastructureError(errors.ErrRepositoryOperation,
+			fmt.Sprintf("Rate limit update cancelled: %v", err))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	s.updates++
+	if s.updates%sweepEvery == 0 {
+		for k, e := range s.entries {
+			if !now.Before(e.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+	}
+	e, found := s.entries[key]
+	if found && !now.Before(e.expiresAt) {
+		found = false
+	}
+	s.entries[key] = memoryEntry{state: fn(e.state, found), expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Len returns the number of keys held, including expired keys not yet swept.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
@@ -0,0 +1,190 @@
+package settlement
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/odds"
+)
+
+// Result is the graded outcome of a selection.
+type Result string
+
+const (
+	Win      Result = "win"
+	Lose     Result = "lose"
+	Void     Result = "void"      // stake returned
+	Push     Result = "push"      // stake returned, e.g. a whole-line handicap tie
+	HalfWin  Result = "half_win"  // half the stake wins, half is returned
+	HalfLose Result = "half_lose" // half the stake loses, half is returned
+	DeadHeat Result = "dead_heat" // the stake is divided by DeadHeatDivisor at full odds
+)
+
+type Selection struct {
+	ID              string
+	Odds            odds.Odds
+	Result          Result
+	DeadHeatDivisor int // number of runners tied for the paid places; dead heats only
+}
+
+// Line is one bet of a settlement: a single, an accumulator, or one
+// combination of a system bet.
+type Line struct {
+	Selections []string
+	Stake      money.Amount
+	Return     money.Amount
+}
+
+// Settlement is the outcome of a bet. Profit is negative when the bet loses
+// money.
+type Settlement struct {
+	Stake  money.Amount
+	Return money.Amount
+	Profit money.Amount
+	Lines  []Line
+}
+
+// Singles settles one line per selection, each staked stake.
+func Singles(stake money.Amount, selections ...Selection) (Settlement, errors.LayerError) {
+	if len(selections) == 0 {
+		return Settlement{}, invalid("Singles need at least one selection", nil)
+	}
+	combos := make([][]Selection, 0, len(selections))
+	for _, sel := range selections {
+		combos = append(combos, []Selection{sel})
+	}
+	return settle(stake, combos)
+}
+
+// Accumulator settles a single line whose return is the stake times the
+// product of the selection factors. Void and push legs count as odds of 1.
+func Accumulator(stake money.Amount, selections ...Selection) (Settlement, errors.LayerError) {
+	if len(selections) < 2 {
+		return Settlement{}, invalid("Accumulators need at least two selections", map[string]any{"selections": len(selections)})
+	}
+	return settle(stake, [][]Selection{selections})
+}
+
+// System settles every combination of the system bet with unitStake per line,
+// so the total stake is unitStake times the number of lines.
+func System(kind SystemBet, unitStake money.Amount, selections ...Selection) (Settlement, errors.LayerError) {
+	shape, ok := systems[kind]
+	if !ok {
+		return Settlement{}, invalid(fmt.Sprintf("Unknown system bet '%s'", kind), map[string]any{"system": string(kind)})
+	}
+	if len(selections) != shape.selections {
+		return Settlement{}, invalid(fmt.Sprintf("%s needs exactly %d selections", kind, shape.selections),
+			map[string]any{"system": string(kind), "selections": len(selections), "required": shape.selections})
+	}
+	var combos [][]Selection
+	for size := shape.minFold; size <= len(selections); size++ {
+		combos = append(combos, combinations(selections, size)...)
+	}
+	return settle(unitStake, combos)
+}
+
+func settle(stake money.Amount, combos [][]Selection) (Settlement, errors.LayerError) {
+	if !stake.IsPositive() {
+		return Settlement{}, invalid("Stake must be positive", map[string]any{"stake": stake.String()})
+	}
+	if err := checkDistinct(combos); err != nil {
+		return Settlement{}, err
+	}
+
+	zero, _ := money.Zero(stake.Currency())
+	s := Settlement{Stake: zero, Return: zero}
+	for _, combo := range combos {
+		line, err := settleLine(stake, combo)
+		if err != nil {
+			return Settlement{}, err
+		}
+		if s.Stake, err = s.Stake.Add(line.Stake); err != nil {
+			return Settlement{}, err
+		}
+		if s.Return, err = s.Return.Add(line.Return); err != nil {
+			return Settlement{}, err
+		}
+		s.Lines = append(s.Lines, line)
+	}
+	profit, err := s.Return.Sub(s.Stake)
+	if err != nil {
+		return Settlement{}, err
+	}
+	s.Profit = profit
+	return s, nil
+}
+
+// settleLine multiplies the exact factors of the line and rounds the return
+// down to the minor unit once, so each line is exact to the cent.
+func settleLine(stake money.Amount, combo []Selection) (Line, errors.LayerError) {
+	product := big.NewRat(1, 1)
+	ids := make([]string, 0, len(combo))
+	for _, sel := range combo {
+		f, err := factor(sel)
+		if err != nil {
+			return Line{}, err
+		}
+		product.Mul(product, f)
+		ids = append(ids, sel.ID)
+	}
+	ret, err := stake.MulRat(product)
+	if err != nil {
+		return Line{}, err
+	}
+	return Line{Selections: ids, Stake: stake, Return: ret}, nil
+}
+
+// factor is the return per unit staked on the selection.
+func factor(sel Selection) (*big.Rat, errors.LayerError) {
+	needsOdds := sel.Result == Win || sel.Result == HalfWin || sel.Result == DeadHeat
+	if needsOdds && sel.Odds.IsZero() {
+		return nil, invalid(fmt.Sprintf("Selection '%s' has no odds", sel.ID), map[string]any{"selection_id": sel.ID, "result": string(sel.Result)})
+	}
+	if sel.Result != DeadHeat && sel.DeadHeatDivisor != 0 {
+		return nil, invalid(fmt.Sprintf("Selection '%s' has a dead-heat divisor but result '%s'", sel.ID, sel.Result),
+			map[string]any{"selection_id": sel.ID, "result": string(sel.Result), "dead_heat_divisor": sel.DeadHeatDivisor})
+	}
+	switch sel.Result {
+	case Win:
+		return sel.Odds.Decimal(), nil
+	case Lose:
+		return new(big.Rat), nil
+	case Void, Push:
+		return big.NewRat(1, 1), nil
+	case HalfWin:
+		f := sel.Odds.Decimal()
+		f.Add(f, big.NewRat(1, 1))
+		return f.Quo(f, big.NewRat(2, 1)), nil
+	case HalfLose:
+		return big.NewRat(1, 2), nil
+	case DeadHeat:
+		if sel.DeadHeatDivisor < 2 {
+			return nil, invalid(fmt.Sprintf("Selection '%s' needs a dead-heat divisor of at least 2", sel.ID),
+				map[string]any{"selection_id": sel.ID, "dead_heat_divisor": sel.DeadHeatDivisor})
+		}
+		f := sel.Odds.Decimal()
+		return f.Quo(f, big.NewRat(int64(sel.DeadHeatDivisor), 1)), nil
+	}
+	return nil, invalid(fmt.Sprintf("Selection '%s' has unknown result '%s'", sel.ID, sel.Result),
+		map[string]any{"selection_id": sel.ID, "result": string(sel.Result)})
+}
+
+// checkDistinct rejects lines that use the same selection twice.
+func checkDistinct(combos [][]Selection) errors.LayerError {
+	for _, combo := range combos {
+		ids := make(map[string]bool, len(combo))
+		for _, sel := range combo {
+			if ids[sel.ID] {
+				return invalid(fmt.Sprintf("Selection '%s' appears more than once", sel.ID), map[string]any{"selection_id": sel.ID})
+			}
+			ids[sel.ID] = true
+		}
+	}
+	return nil
+}
+
+func invalid(message string, details map[string]any) errors.LayerError {
+	return errors.NewBusinessRuleError(errors.ErrInvalidSettlement, message, details)
+}
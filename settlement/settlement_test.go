@@ -0,0 +1,208 @@
+package settlement
+
+import (
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/odds"
+)
+
+func mxn(s string) money.Amount {
+	return money.MustParse(s, "MXN")
+}
+
+func sel(id, price string, result Result) Selection {
+	return Selection{ID: id, Odds: odds.MustParse(odds.Decimal, price), Result: result}
+}
+
+func deadHeat(id, price string, divisor int) Selection {
+	s := sel(id, price, DeadHeat)
+	s.DeadHeatDivisor = divisor
+	return s
+}
+
+func TestSingles(t *testing.T) {
+	tests := []struct {
+		name       string
+		selection  Selection
+		wantReturn string
+		wantProfit string
+	}{
+		{"Win", sel("a", "2.50", Win), "25.00", "15.00"},
+		{"Lose", sel("a", "2.50", Lose), "0.00", "-10.00"},
+		{"Void", sel("a", "2.50", Void), "10.00", "0.00"},
+		{"Push", sel("a", "1.90", Push), "10.00", "0.00"},
+		{"Half win", sel("a", "1.90", HalfWin), "14.50", "4.50"},
+		{"Half lose", sel("a", "1.90", HalfLose), "5.00", "-5.00"},
+		{"Dead heat of two", deadHeat("a", "5.00", 2), "25.00", "15.00"},
+		{"Dead heat of three", deadHeat("a", "4.00", 3), "13.33", "3.33"},
+		{"Lose without odds", Selection{ID: "a", Result: Lose}, "0.00", "-10.00"},
+		{"Void without odds", Selection{ID: "a", Result: Void}, "10.00", "0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Singles(mxn("10"), tt.selection)
+			if err != nil {
+				t.Fatalf("Singles() unexpected error: %v", err)
+			}
+			if s.Return.String() != tt.wantReturn || s.Profit.String() != tt.wantProfit {
+				t.Errorf("return/profit = %s/%s, want %s/%s", s.Return, s.Profit, tt.wantReturn, tt.wantProfit)
+			}
+		})
+	}
+}
+
+func TestMultipleSingles(t *testing.T) {
+	s, err := Singles(mxn("10"), sel("a", "2.00", Win), sel("b", "3.00", Lose), sel("c", "1.50", Void))
+	if err != nil {
+		t.Fatalf("Singles() unexpected error: %v", err)
+	}
+	if s.Stake.String() != "30.00" || s.Return.String() != "30.00" || len(s.Lines) != 3 {
+		t.Errorf("Singles() = stake %s return %s lines %d, want 30.00 30.00 3", s.Stake, s.Return, len(s.Lines))
+	}
+}
+
+func TestAccumulator(t *testing.T) {
+	tests := []struct {
+		name       string
+		selections []Selection
+		wantReturn string
+	}{
+		{"All win", []Selection{sel("a", "2.00", Win), sel("b", "1.50", Win), sel("c", "3.00", Win)}, "90.00"},
+		{"One loser", []Selection{sel("a", "2.00", Win), sel("b", "1.50", Lose), sel("c", "3.00", Win)}, "0.00"},
+		{"Void leg counts as one", []Selection{sel("a", "2.00", Win), sel("b", "1.50", Void), sel("c", "3.00", Win)}, "60.00"},
+		{"All void returns stake", []Selection{sel("a", "2.00", Void), sel("b", "1.50", Push)}, "10.00"},
+		{"Half win leg", []Selection{sel("a", "2.00", Win), sel("b", "1.90", HalfWin)}, "29.00"},
+		{"Half lose leg", []Selection{sel("a", "2.00", Win), sel("b", "1.90", HalfLose)}, "10.00"},
+		{"Dead heat leg", []Selection{sel("a", "2.00", Win), deadHeat("b", "6.00", 2)}, "60.00"},
+		{"Rounds down once per line", []Selection{sel("a", "1.33", Win), sel("b", "1.33", Win), sel("c", "1.33", Win)}, "23.52"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Accumulator(mxn("10"), tt.selections...)
+			if err != nil {
+				t.Fatalf("Accumulator() unexpected error: %v", err)
+			}
+			if s.Return.String() != tt.wantReturn {
+				t.Errorf("Return = %s, want %s", s.Return, tt.wantReturn)
+			}
+			if s.Stake.String() != "10.00" || len(s.Lines) != 1 {
+				t.Errorf("Stake = %s, lines = %d, want 10.00 and 1", s.Stake, len(s.Lines))
+			}
+		})
+	}
+}
+
+func TestSystem_LineCounts(t *testing.T) {
+	tests := []struct {
+		kind       SystemBet
+		selections int
+		wantLines  int
+	}{
+		{Trixie, 3, 4},
+		{Patent, 3, 7},
+		{Yankee, 4, 11},
+		{Lucky15, 4, 15},
+		{Canadian, 5, 26},
+		{Lucky31, 5, 31},
+		{Heinz, 6, 57},
+		{Lucky63, 6, 63},
+		{SuperHeinz, 7, 120},
+		{Goliath, 8, 247},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			var selections []Selection
+			for i := 0; i < tt.selections; i++ {
+				selections = append(selections, sel(string(rune('a'+i)), "2.00", Win))
+			}
+			s, err := System(tt.kind, mxn("1"), selections...)
+			if err != nil {
+				t.Fatalf("System() unexpected error: %v", err)
+			}
+			if len(s.Lines) != tt.wantLines {
+				t.Errorf("lines = %d, want %d", len(s.Lines), tt.wantLines)
+			}
+			if s.Stake.Minor() != int64(tt.wantLines)*100 {
+				t.Errorf("Stake = %s, want %d.00", s.Stake, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestSystem_Returns(t *testing.T) {
+	tests := []struct {
+		name       string
+		kind       SystemBet
+		selections []Selection
+		wantReturn string
+	}{
+		// Doubles 6+4+6 = 16, treble 12.
+		{"Trixie all win", Trixie, []Selection{sel("a", "2.00", Win), sel("b", "3.00", Win), sel("c", "2.00", Win)}, "28.00"},
+		// Only the a/c double survives.
+		{"Trixie one loser", Trixie, []Selection{sel("a", "2.00", Win), sel("b", "3.00", Lose), sel("c", "2.00", Win)}, "4.00"},
+		// Singles 2+2, double 4.
+		{"Patent one loser", Patent, []Selection{sel("a", "2.00", Win), sel("b", "3.00", Lose), sel("c", "2.00", Win)}, "8.00"},
+		// Void leg: doubles 2+2+4 = 8, treble 4.
+		{"Trixie with void", Trixie, []Selection{sel("a", "2.00", Win), sel("b", "3.00", Void), sel("c", "2.00", Win)}, "12.00"},
+		// Doubles 6 x 4 = 24, trebles 4 x 8 = 32, fourfold 16.
+		{"Yankee all win", Yankee, []Selection{sel("a", "2.00", Win), sel("b", "2.00", Win), sel("c", "2.00", Win), sel("d", "2.00", Win)}, "72.00"},
+		{"Yankee two losers", Yankee, []Selection{sel("a", "2.00", Win), sel("b", "2.00", Lose), sel("c", "2.00", Win), sel("d", "2.00", Lose)}, "4.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := System(tt.kind, mxn("1"), tt.selections...)
+			if err != nil {
+				t.Fatalf("System() unexpected error: %v", err)
+			}
+			if s.Return.String() != tt.wantReturn {
+				t.Errorf("Return = %s, want %s", s.Return, tt.wantReturn)
+			}
+		})
+	}
+}
+
+func TestInvalidInputs(t *testing.T) {
+	tests := []struct {
+		name   string
+		settle func() (Settlement, errors.LayerError)
+	}{
+		{"Zero stake", func() (Settlement, errors.LayerError) { return Singles(mxn("0"), sel("a", "2.00", Win)) }},
+		{"Uninitialized stake", func() (Settlement, errors.LayerError) { return Singles(money.Amount{}, sel("a", "2.00", Win)) }},
+		{"No selections", func() (Settlement, errors.LayerError) { return Singles(mxn("10")) }},
+		{"Single leg accumulator", func() (Settlement, errors.LayerError) { return Accumulator(mxn("10"), sel("a", "2.00", Win)) }},
+		{"Duplicate leg", func() (Settlement, errors.LayerError) {
+			return Accumulator(mxn("10"), sel("a", "2.00", Win), sel("a", "2.00", Win))
+		}},
+		{"Unknown result", func() (Settlement, errors.LayerError) { return Singles(mxn("10"), sel("a", "2.00", "abandoned")) }},
+		{"Win without odds", func() (Settlement, errors.LayerError) { return Singles(mxn("10"), Selection{ID: "a", Result: Win}) }},
+		{"Dead heat without divisor", func() (Settlement, errors.LayerError) { return Singles(mxn("10"), deadHeat("a", "3.00", 0)) }},
+		{"Dead heat divisor of one", func() (Settlement, errors.LayerError) { return Singles(mxn("10"), deadHeat("a", "3.00", 1)) }},
+		{"Divisor on a win", func() (Settlement, errors.LayerError) {
+			s := sel("a", "3.00", Win)
+			s.DeadHeatDivisor = 2
+			return Singles(mxn("10"), s)
+		}},
+		{"Wrong system size", func() (Settlement, errors.LayerError) {
+			return System(Yankee, mxn("1"), sel("a", "2.00", Win), sel("b", "2.00", Win), sel("c", "2.00", Win))
+		}},
+		{"Unknown system", func() (Settlement, errors.LayerError) { return System("flag", mxn("1"), sel("a", "2.00", Win)) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.settle()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if err.Code() != errors.ErrInvalidSettlement || err.Type() != errors.BusinessRuleError {
+				t.Errorf("error = %v/%v, want %v/%v", err.Code(), err.Type(), errors.ErrInvalidSettlement, errors.BusinessRuleError)
+			}
+		})
+	}
+}
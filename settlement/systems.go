@@ -0,0 +1,65 @@
+package settlement
+
+// SystemBet names a full-cover bet made of every combination of its
+// selections from a minimum fold size upwards.
+type SystemBet string
+
+const (
+	Trixie     SystemBet = "trixie"      // 3 selections: 3 doubles, 1 treble
+	Patent     SystemBet = "patent"      // 3 selections: Trixie plus 3 singles
+	Yankee     SystemBet = "yankee"      // 4 selections: 11 bets, doubles upwards
+	Lucky15    SystemBet = "lucky15"     // 4 selections: Yankee plus 4 singles
+	Canadian   SystemBet = "canadian"    // 5 selections: 26 bets, doubles upwards
+	Lucky31    SystemBet = "lucky31"     // 5 selections: Canadian plus 5 singles
+	Heinz      SystemBet = "heinz"       // 6 selections: 57 bets, doubles upwards
+	Lucky63    SystemBet = "lucky63"     // 6 selections: Heinz plus 6 singles
+	SuperHeinz SystemBet = "super_heinz" // 7 selections: 120 bets, doubles upwards
+	Goliath    SystemBet = "goliath"     // 8 selections: 247 bets, doubles upwards
+)
+
+type systemShape struct {
+	selections int
+	minFold    int
+}
+
+var systems = map[SystemBet]systemShape{
+	Trixie:     {3, 2},
+	Patent:     {3, 1},
+	Yankee:     {4, 2},
+	Lucky15:    {4, 1},
+	Canadian:   {5, 2},
+	Lucky31:    {5, 1},
+	Heinz:      {6, 2},
+	Lucky63:    {6, 1},
+	SuperHeinz: {7, 2},
+	Goliath:    {8, 2},
+}
+
+// combinations returns every size-k subset of selections in lexicographic
+// order of their positions.
+func combinations(selections []Selection, k int) [][]Selection {
+	var out [][]Selection
+	idx := make([]int, k)
+	for i := range idx {
+		idx[i] = i
+	}
+	for {
+		combo := make([]Selection, k)
+		for i, j := range idx {
+			combo[i] = selections[j]
+		}
+		out = append(out, combo)
+
+		i := k - 1
+		for i >= 0 && idx[i] == len(selections)-k+i {
+			i--
+		}
+		if i < 0 {
+			return out
+		}
+		idx[i]++
+		for j := i + 1; j < k; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+}
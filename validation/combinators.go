@@ -0,0 +1,241 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// And passes when every option passes. All options run, so a failure reports
+// every unmet rule in Details["causes"].
+func And(opts ...ValidationOption) ValidationOption {
+	return describe(func(field string, value any) errors.LayerError {
+		causes := runAll(field, value, opts)
+		if len(causes) == 0 {
+			return nil
+		}
+		return mergeCauses(field, fmt.Sprintf("Field '%s' is invalid", field), causes)
+	}, func(p *schemaProbe) { p.run(opts) })
+}
+
+// Or passes when at least one option passes.
+func Or(opts ...ValidationOption) ValidationOption {
+	return describe(func(field string, value any) errors.LayerError {
+		var causes []errors.LayerError
+		for _, opt := range opts {
+			err := opt(field, value)
+			if err == nil {
+				return nil
+			}
+			causes = append(causes, err)
+		}
+		if len(causes) == 0 {
+			return nil
+		}
+		if len(causes) == 1 {
+			return causes[0]
+		}
+		return errors.NewValidationError(errors.ErrInvalidFormat,
+			fmt.Sprintf("Field '%s' must satisfy at least one of %d rules", field, len(causes)),
+			map[string]any{"field": field, "causes": describeCauses(causes)})
+	}, func(p *schemaProbe) {
+		anyOf := make([]*Schema, 0, len(opts))
+		for _, opt := range opts {
+			anyOf = append(anyOf, p.child(p.typ, opt))
+		}
+		p.schema.merge(&Schema{AnyOf: anyOf})
+	})
+}
+
+// Not passes when the option fails.
+func Not(opt ValidationOption, msg ...string) ValidationOption {
+	return describe(func(field string, value any) errors.LayerError {
+		message := fmt.Sprintf("Field '%s' must not satisfy the rule", field)
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		if opt(field, value) == nil {
+			return errors.NewValidationError(errors.ErrInvalidFormat, message, map[string]any{"field": field})
+		}
+		return nil
+	}, func(p *schemaProbe) { p.schema.merge(&Schema{Not: p.child(p.typ, opt)}) })
+}
+
+// Optional skips the options when the value is nil or the zero value of its
+// type.
+func Optional(opts ...ValidationOption) ValidationOption {
+	and := And(opts...)
+	return describe(func(field string, value any) errors.LayerError {
+		if isZero(value) {
+			return nil
+		}
+		return and(field, value)
+	}, func(p *schemaProbe) {
+		inner := p.child(p.typ, opts...)
+		if inner.isEmpty() {
+			return
+		}
+		if zero := p.zero(); zero != nil {
+			p.schema.merge(&Schema{AnyOf: []*Schema{{Enum: []any{zero}}, inner}})
+			return
+		}
+		p.schema.merge(inner)
+	})
+}
+
+// Each applies the options to every element of a slice or array. Element
+// failures are reported with the field name "field[i]".
+func Each(opts ...ValidationOption) ValidationOption {
+	return describe(func(field string, value any) errors.LayerError {
+		val := reflect.ValueOf(indirect(value))
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return notCollection(field, "slice or array")
+		}
+		var causes []errors.LayerError
+		invalid := 0
+		for i := 0; i < val.Len(); i++ {
+			name := fmt.Sprintf("%s[%d]", field, i)
+			failed := runAll(name, indirect(val.Index(i).Interface()), opts)
+			if len(failed) > 0 {
+				invalid++
+			}
+			causes = append(causes, failed...)
+		}
+		return mergeElementCauses(field, invalid, causes)
+	}, func(p *schemaProbe) { p.into(&p.schema.Items, p.elem(), opts) })
+}
+
+// Keys applies the options to every key of a map.
+func Keys(opts ...ValidationOption) ValidationOption {
+	return describe(mapRule(opts, true), func(p *schemaProbe) { p.into(&p.schema.PropertyNames, p.key(), opts) })
+}
+
+// Values applies the options to every value of a map. Value failures are
+// reported with the field name "field[key]".
+func Values(opts ...ValidationOption) ValidationOption {
+	return describe(mapRule(opts, false), func(p *schemaProbe) { p.into(&p.schema.AdditionalProperties, p.elem(), opts) })
+}
+
+func mapRule(opts []ValidationOption, keys bool) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		val := reflect.ValueOf(indirect(value))
+		if val.Kind() != reflect.Map {
+			return notCollection(field, "map")
+		}
+		mapKeys := val.MapKeys()
+		sort.Slice(mapKeys, func(i, j int) bool {
+			return fmt.Sprint(mapKeys[i].Interface()) < fmt.Sprint(mapKeys[j].Interface())
+		})
+		var causes []errors.LayerError
+		invalid := 0
+		for _, k := range mapKeys {
+			name := fmt.Sprintf("%s[%v]", field, k.Interface())
+			target := val.MapIndex(k).Interface()
+			if keys {
+				target = k.Interface()
+			}
+			failed := runAll(name, indirect(target), opts)
+			if len(failed) > 0 {
+				invalid++
+			}
+			causes = append(causes, failed...)
+		}
+		return mergeElementCauses(field, invalid, causes)
+	}
+}
+
+// Message replaces the message of the error returned by opt, keeping its code
+// and details. It is useful for validators whose arguments are variadic.
+func Message(opt ValidationOption, msg string) ValidationOption {
+	return describe(func(field string, value any) errors.LayerError {
+		err := opt(field, value)
+		if err == nil {
+			return nil
+		}
+		return rebuild(err, msg, err.Details())
+	}, func(p *schemaProbe) { p.run([]ValidationOption{opt}) })
+}
+
+// rebuild returns an error with the layer, type and code of err and the given
+// message and details.
+func rebuild(err errors.LayerError, message string, details map[string]any) errors.LayerError {
+	switch err.Layer() {
+	case errors.InfrastructureLayer:
+		return errors.NewInfrastructureError(err.Code(), message, details)
+	case errors.DomainLayer:
+		return errors.NewDomainError(err.Code(), err.Type(), message, details)
+	default:
+		return errors.NewApplicationError(err.Code(), err.Type(), message, details)
+	}
+}
+
+func runAll(field string, value any, opts []ValidationOption) []errors.LayerError {
+	var causes []errors.LayerError
+	for _, opt := range opts {
+		if err := opt(field, value); err != nil {
+			causes = append(causes, err)
+		}
+	}
+	return causes
+}
+
+// mergeElementCauses folds the failures of invalid elements, which may each
+// have failed several options, into one error.
+func mergeElementCauses(field string, invalid int, causes []errors.LayerError) errors.LayerError {
+	if len(causes) == 0 {
+		return nil
+	}
+	return mergeCauses(field, fmt.Sprintf("Field '%s' contains %d invalid elements", field, invalid), causes)
+}
+
+// mergeCauses folds several failures into one error. A single cause is
+// returned unchanged; otherwise the code, layer and type of the causes are
+// kept when they all agree, and a validation error coded ErrInvalidFormat is
+// returned when they do not.
+func mergeCauses(field, message string, causes []errors.LayerError) errors.LayerError {
+	if len(causes) == 1 {
+		return causes[0]
+	}
+	details := map[string]any{"field": field, "causes": describeCauses(causes)}
+	first := causes[0]
+	for _, c := range causes[1:] {
+		if c.Code() != first.Code() || c.Layer() != first.Layer() || c.Type() != first.Type() {
+			return errors.NewValidationError(errors.ErrInvalidFormat, message, details)
+		}
+	}
+	return rebuild(first, message, details)
+}
+
+func describeCauses(causes []errors.LayerError) []map[string]any {
+	out := make([]map[string]any, 0, len(causes))
+	for _, c := range causes {
+		out = append(out, map[string]any{
+			"code":    string(c.Code()),
+			"message": c.Error(),
+			"details": c.Details(),
+		})
+	}
+	return out
+}
+
+func notCollection(field, kind string) errors.LayerError {
+	return errors.NewValidationError(errors.ErrInvalidFormat,
+		fmt.Sprintf("Field '%s' must be a %s", field, kind),
+		map[string]any{"field": field})
+}
+
+func isZero(value any) bool {
+	if value == nil {
+		return true
+	}
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return val.IsNil()
+	case reflect.Slice, reflect.Map:
+		return val.Len() == 0
+	}
+	return val.IsZero()
+}
@@ -0,0 +1,126 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+var phone = Pattern(`^\+[1-9]\d{7,14}$`)
+
+func TestCombinators(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		opt     ValidationOption
+		wantErr bool
+	}{
+		{"And passes", "john@example.com", And(Required(), Email()), false},
+		{"And fails", "x", And(Email(), MinLength(3)), true},
+		{"Or accepts email", "john@example.com", Or(Email(), phone), false},
+		{"Or accepts phone", "+525512345678", Or(Email(), phone), false},
+		{"Or rejects both", "nope", Or(Email(), phone), true},
+		{"Not rejects match", "admin", Not(Pattern(`^admin$`)), true},
+		{"Not accepts mismatch", "john", Not(Pattern(`^admin$`)), false},
+		{"Optional skips empty string", "", Optional(Email()), false},
+		{"Optional skips nil", nil, Optional(Email()), false},
+		{"Optional validates present", "bad", Optional(Email()), true},
+		{"Each passes", []string{"a@b.co", "c@d.co"}, Each(Email()), false},
+		{"Each fails", []string{"a@b.co", "bad"}, Each(Email()), true},
+		{"Each rejects non collection", "a@b.co", Each(Email()), true},
+		{"Keys passes", map[string]int{"mx": 1}, Keys(Pattern(`^[a-z]{2}$`)), false},
+		{"Keys fails", map[string]int{"mex": 1}, Keys(Pattern(`^[a-z]{2}$`)), true},
+		{"Values fails", map[string]string{"home": "bad"}, Values(Email()), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(Field("field", tt.value, tt.opt))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOr_KeepsCauses(t *testing.T) {
+	err := Validate(Field("contact", "nope", Or(Email(), phone)))
+	if err == nil {
+		t.Fatal("Expected error when no alternative matches")
+	}
+	if err.Code() != errors.ErrInvalidFormat {
+		t.Errorf("Code() = %v, want %v", err.Code(), errors.ErrInvalidFormat)
+	}
+	causes, ok := err.Details()["causes"].([]map[string]any)
+	if !ok || len(causes) != 2 {
+		t.Fatalf("Details[causes] = %v, want 2 causes", err.Details()["causes"])
+	}
+	if causes[0]["code"] != string(errors.ErrInvalidEmail) {
+		t.Errorf("causes[0][code] = %v, want %v", causes[0]["code"], errors.ErrInvalidEmail)
+	}
+}
+
+func TestEach_ReportsElementPaths(t *testing.T) {
+	err := Validate(Field("emails", []string{"bad", "a@b.co", "worse"}, Each(Email())))
+	if err == nil {
+		t.Fatal("Expected error for invalid elements")
+	}
+	if err.Code() != errors.ErrInvalidEmail {
+		t.Errorf("Code() = %v, want %v", err.Code(), errors.ErrInvalidEmail)
+	}
+	causes := err.Details()["causes"].([]map[string]any)
+	if len(causes) != 2 {
+		t.Fatalf("len(causes) = %d, want 2", len(causes))
+	}
+	first := causes[0]["details"].(map[string]any)
+	if first["field"] != "emails[0]" {
+		t.Errorf("causes[0] field = %v, want emails[0]", first["field"])
+	}
+	second := causes[1]["details"].(map[string]any)
+	if second["field"] != "emails[2]" {
+		t.Errorf("causes[1] field = %v, want emails[2]", second["field"])
+	}
+}
+
+func TestEach_SingleFailureIsReturnedAsIs(t *testing.T) {
+	err := Validate(Field("emails", []string{"a@b.co", "bad"}, Each(Email())))
+	if err == nil {
+		t.Fatal("Expected error for invalid element")
+	}
+	if err.Details()["field"] != "emails[1]" {
+		t.Errorf("Details[field] = %v, want emails[1]", err.Details()["field"])
+	}
+}
+
+func TestEach_CountsInvalidElements(t *testing.T) {
+	err := Validate(Field("nicknames", []string{"ok_name", "X"}, Each(MinLength(3), Pattern(`^[a-z_]+$`))))
+	if err == nil {
+		t.Fatal("Expected error for invalid element")
+	}
+	if want := "Field 'nicknames' contains 1 invalid elements"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestEach_KeepsLayerAndType(t *testing.T) {
+	err := Validate(Field("stakes", []string{"5.00", "900.00"}, Each(StakeBetween("10.00", "500.00", "MXN"))))
+	if err == nil {
+		t.Fatal("Expected error for stakes out of range")
+	}
+	if err.Layer() != errors.DomainLayer || err.Type() != errors.BusinessRuleError || err.Code() != errors.ErrStakeOutOfRange {
+		t.Errorf("Each() = %s %s %s, want domain business_rule %s", err.Layer(), err.Type(), err.Code(), errors.ErrStakeOutOfRange)
+	}
+}
+
+func TestMessage_KeepsLayerAndType(t *testing.T) {
+	err := Validate(Field("stake", "5.00", Message(StakeBetween("10.00", "500.00", "MXN"), "Stake out of range")))
+	if err == nil {
+		t.Fatal("Expected error for stake below minimum")
+	}
+	if err.Error() != "Stake out of range" {
+		t.Errorf("Error() = %q, want the custom message", err.Error())
+	}
+	if err.Layer() != errors.DomainLayer || err.Type() != errors.BusinessRuleError || err.Code() != errors.ErrStakeOutOfRange {
+		t.Errorf("Message() = %s %s %s, want domain business_rule %s", err.Layer(), err.Type(), err.Code(), errors.ErrStakeOutOfRange)
+	}
+}
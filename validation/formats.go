@@ -0,0 +1,172 @@
+package validation
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+)
+
+var (
+	uuidRegex  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	phoneRegex = regexp.MustCompile(`^\+[1-9]\d{1,14}$`)
+	slugRegex  = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	hexRegex   = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+)
+
+// UUID validates the canonical 8-4-4-4-12 form.
+func UUID(msg ...string) ValidationOption {
+	return UUIDVersion(nil, msg...)
+}
+
+// UUIDVersion validates a UUID like UUID. When versions are given, the
+// version nibble must be one of them and the variant must be RFC 9562. A
+// version outside 1-8, the versions RFC 9562 defines, is a programming error:
+// the option then fails every value with an ErrInternal internal error, as
+// Struct does for malformed tags.
+func UUIDVersion(versions []int, msg ...string) ValidationOption {
+	for _, v := range versions {
+		if v < 1 || v > 8 {
+			cause := fmt.Errorf("validation: UUID version %d is not between 1 and 8", v)
+			return func(field string, value any) errors.LayerError {
+				return errors.WithCause(errors.NewApplicationError(errors.ErrInternal, errors.InternalError, "Internal error"), cause)
+			}
+		}
+	}
+	return describe(formatRule(errors.ErrInvalidUUID, "a valid UUID", func(s string) bool {
+		if !uuidRegex.MatchString(s) {
+			return false
+		}
+		if len(versions) == 0 {
+			return true
+		}
+		if !strings.ContainsRune("89abAB", rune(s[19])) {
+			return false
+		}
+		for _, v := range versions {
+			if s[14] == byte('0'+v) {
+				return true
+			}
+		}
+		return false
+	}, msg...), func(p *schemaProbe) { p.schema.merge(&Schema{Format: "uuid"}) })
+}
+
+// URL validates an absolute http or https URL with a host.
+func URL(msg ...string) ValidationOption {
+	return URLWithSchemes([]string{"http", "https"}, msg...)
+}
+
+// URLWithSchemes validates an absolute URL with a host whose scheme is in the
+// allow-list.
+func URLWithSchemes(schemes []string, msg ...string) ValidationOption {
+	return describe(formatRule(errors.ErrInvalidURL, "a valid URL", func(s string) bool {
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" {
+			return false
+		}
+		for _, scheme := range schemes {
+			if strings.EqualFold(u.Scheme, scheme) {
+				return true
+			}
+		}
+		return false
+	}, msg...), func(p *schemaProbe) { p.schema.merge(&Schema{Format: "uri"}) })
+}
+
+func PhoneE164(msg ...string) ValidationOption {
+	return describe(formatRule(errors.ErrInvalidPhone, "an E.164 phone number", phoneRegex.MatchString, msg...), func(p *schemaProbe) { p.schema.merge(&Schema{Pattern: phoneRegex.String()}) })
+}
+
+// CountryISO3166 validates an upper-case ISO 3166-1 alpha-2 country code.
+func CountryISO3166(msg ...string) ValidationOption {
+	return describe(formatRule(errors.ErrInvalidCountry, "an ISO 3166-1 alpha-2 country code", func(s string) bool {
+		return countryCodes[s]
+	}, msg...), func(p *schemaProbe) { p.schema.merge(&Schema{Enum: stringEnum(sortedKeys(countryCodes))}) })
+}
+
+// CurrencyISO4217 validates an upper-case ISO 4217 alphabetic currency code.
+func CurrencyISO4217(msg ...string) ValidationOption {
+	return describe(formatRule(errors.ErrInvalidCurrency, "an ISO 4217 currency code", func(s string) bool {
+		return money.IsCurrency(s)
+	}, msg...), func(p *schemaProbe) { p.schema.merge(&Schema{Enum: stringEnum(money.Currencies())}) })
+}
+
+func IP(msg ...string) ValidationOption {
+	return describe(formatRule(errors.ErrInvalidIP, "a valid IP address", func(s string) bool {
+		_, err := netip.ParseAddr(s)
+		return err == nil
+	}, msg...), func(p *schemaProbe) { p.schema.merge(&Schema{AnyOf: []*Schema{{Format: "ipv4"}, {Format: "ipv6"}}}) })
+}
+
+func IPv4(msg ...string) ValidationOption {
+	return describe(formatRule(errors.ErrInvalidIP, "a valid IPv4 address", func(s string) bool {
+		addr, err := netip.ParseAddr(s)
+		return err == nil && addr.Is4()
+	}, msg...), func(p *schemaProbe) { p.schema.merge(&Schema{Format: "ipv4"}) })
+}
+
+func IPv6(msg ...string) ValidationOption {
+	return describe(formatRule(errors.ErrInvalidIP, "a valid IPv6 address", func(s string) bool {
+		addr, err := netip.ParseAddr(s)
+		return err == nil && addr.Is6()
+	}, msg...), func(p *schemaProbe) { p.schema.merge(&Schema{Format: "ipv6"}) })
+}
+
+func CIDR(msg ...string) ValidationOption {
+	return describe(formatRule(errors.ErrInvalidCIDR, "a valid CIDR block", func(s string) bool {
+		_, err := netip.ParsePrefix(s)
+		return err == nil
+	}, msg...), func(p *schemaProbe) { p.schema.merge(&Schema{Pattern: cidrPattern}) })
+}
+
+// cidrPattern describes the shape of an IPv4 or IPv6 CIDR block for JSON
+// Schema, which has no CIDR format.
+const cidrPattern = `^[0-9A-Fa-f.:]+/[0-9]{1,3}$`
+
+// Slug validates lower-case alphanumeric words separated by single hyphens.
+func Slug(msg ...string) ValidationOption {
+	return describe(formatRule(errors.ErrInvalidSlug, "a valid slug", slugRegex.MatchString, msg...), func(p *schemaProbe) { p.schema.merge(&Schema{Pattern: slugRegex.String()}) })
+}
+
+// Base64 accepts standard or URL-safe encoding, padded or unpadded.
+// Base64 validates non-empty base64 strings, like Hex.
+func Base64(msg ...string) ValidationOption {
+	return describe(formatRule(errors.ErrInvalidBase64, "valid base64", func(s string) bool {
+		if s == "" {
+			return false
+		}
+		for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+			if _, err := enc.DecodeString(s); err == nil {
+				return true
+			}
+		}
+		return false
+	}, msg...), func(p *schemaProbe) { p.schema.merge(&Schema{ContentEncoding: "base64"}) })
+}
+
+func Hex(msg ...string) ValidationOption {
+	return describe(formatRule(errors.ErrInvalidHex, "a hexadecimal string", hexRegex.MatchString, msg...), func(p *schemaProbe) { p.schema.merge(&Schema{Pattern: hexRegex.String()}) })
+}
+
+func formatRule(code errors.ErrorCode, what string, valid func(string) bool, msg ...string) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		message := fmt.Sprintf("Field '%s' must be %s", field, what)
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		str, ok := value.(string)
+		if !ok {
+			return errors.NewValidationError(code, message, map[string]any{"field": field})
+		}
+		if !valid(str) {
+			return errors.NewValidationError(code, message, map[string]any{"field": field, "value": str})
+		}
+		return nil
+	}
+}
@@ -0,0 +1,142 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/odds"
+)
+
+func TestFormatValidators(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		opt      ValidationOption
+		wantCode errors.ErrorCode
+	}{
+		{"UUID any version", "123e4567-e89b-12d3-a456-426614174000", UUID(), ""},
+		{"UUID v4", "f47ac10b-58cc-4372-a567-0e02b2c3d479", UUIDVersion([]int{4}), ""},
+		{"UUID v7", "01890a5d-ac96-774b-bcce-b302099a8057", UUIDVersion([]int{4, 7}), ""},
+		{"UUID wrong version", "123e4567-e89b-12d3-a456-426614174000", UUIDVersion([]int{4}), errors.ErrInvalidUUID},
+		{"UUID wrong variant", "f47ac10b-58cc-4372-c567-0e02b2c3d479", UUIDVersion([]int{4}), errors.ErrInvalidUUID},
+		{"UUID malformed", "f47ac10b58cc4372a5670e02b2c3d479", UUID(), errors.ErrInvalidUUID},
+		{"URL https", "https://betmates.mx/bets?id=1", URL(), ""},
+		{"URL custom scheme", "wss://stream.betmates.mx", URLWithSchemes([]string{"wss"}), ""},
+		{"URL scheme not allowed", "ftp://betmates.mx", URL(), errors.ErrInvalidURL},
+		{"URL relative", "/bets/1", URL(), errors.ErrInvalidURL},
+		{"Phone E.164", "+525512345678", PhoneE164(), ""},
+		{"Phone without plus", "525512345678", PhoneE164(), errors.ErrInvalidPhone},
+		{"Phone too long", "+1234567890123456", PhoneE164(), errors.ErrInvalidPhone},
+		{"Country", "MX", CountryISO3166(), ""},
+		{"Country lower case", "mx", CountryISO3166(), errors.ErrInvalidCountry},
+		{"Country unassigned", "ZZ", CountryISO3166(), errors.ErrInvalidCountry},
+		{"Currency", "MXN", CurrencyISO4217(), ""},
+		{"Currency unknown", "ABC", CurrencyISO4217(), errors.ErrInvalidCurrency},
+		{"IP v4", "192.168.0.1", IP(), ""},
+		{"IP v6", "2001:db8::1", IP(), ""},
+		{"IP invalid", "256.0.0.1", IP(), errors.ErrInvalidIP},
+		{"IPv4 rejects v6", "2001:db8::1", IPv4(), errors.ErrInvalidIP},
+		{"IPv6 rejects v4", "10.0.0.1", IPv6(), errors.ErrInvalidIP},
+		{"CIDR", "10.0.0.0/8", CIDR(), ""},
+		{"CIDR without mask", "10.0.0.0", CIDR(), errors.ErrInvalidCIDR},
+		{"Slug", "liga-mx-2024", Slug(), ""},
+		{"Slug double hyphen", "liga--mx", Slug(), errors.ErrInvalidSlug},
+		{"Slug upper case", "Liga-MX", Slug(), errors.ErrInvalidSlug},
+		{"Base64 padded", "aGVsbG8=", Base64(), ""},
+		{"Base64 raw URL", "aGVsbG8_-w", Base64(), ""},
+		{"Base64 invalid", "not base64!", Base64(), errors.ErrInvalidBase64},
+		{"Base64 empty", "", Base64(), errors.ErrInvalidBase64},
+		{"Hex empty", "", Hex(), errors.ErrInvalidHex},
+		{"Hex", "deadBEEF", Hex(), ""},
+		{"Hex invalid", "0xdeadbeef", Hex(), errors.ErrInvalidHex},
+		{"Non string value", 42, Slug(), errors.ErrInvalidSlug},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(Field("field", tt.value, tt.opt))
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() expected %v, got nil", tt.wantCode)
+			}
+			if err.Code() != tt.wantCode {
+				t.Errorf("Code() = %v, want %v", err.Code(), tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestFormatValidators_CustomMessage(t *testing.T) {
+	err := Validate(Field("country", "XX", CountryISO3166("Unsupported country")))
+	if err == nil || err.Error() != "Unsupported country" {
+		t.Errorf("Error() = %v, want custom message", err)
+	}
+
+	err = Validate(Field("id", "nope", Message(UUIDVersion([]int{4}), "Invalid bet id")))
+	if err == nil {
+		t.Fatal("Expected error for invalid UUID")
+	}
+	if err.Error() != "Invalid bet id" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "Invalid bet id")
+	}
+	if err.Code() != errors.ErrInvalidUUID || err.Details()["value"] != "nope" {
+		t.Errorf("Message() should keep code and details, got %v %v", err.Code(), err.Details())
+	}
+}
+
+func TestFormatValidators_MessageParameter(t *testing.T) {
+	for _, opt := range []ValidationOption{UUID("Bad id"), UUIDVersion([]int{4}, "Bad id"), URL("Bad id"), URLWithSchemes([]string{"wss"}, "Bad id")} {
+		if err := Validate(Field("id", "nope", opt)); err == nil || err.Error() != "Bad id" {
+			t.Errorf("Error() = %v, want the custom message", err)
+		}
+	}
+}
+
+func TestUUIDVersion_RejectsUnknownVersions(t *testing.T) {
+	for _, v := range []int{0, 9, 16} {
+		err := Validate(Field("id", "f47ac10b-58cc-4372-a567-0e02b2c3d479", UUIDVersion([]int{4, v})))
+		if err == nil || err.Code() != errors.ErrInternal || err.Type() != errors.InternalError {
+			t.Errorf("UUIDVersion(%d) error = %v, want %s internal error", v, err, errors.ErrInternal)
+		}
+	}
+}
+
+func TestOdds(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		format  odds.Format
+		wantErr bool
+	}{
+		{"Decimal", "2.50", odds.Decimal, false},
+		{"Decimal not above one", "1.00", odds.Decimal, true},
+		{"Fractional", "3/2", odds.Fractional, false},
+		{"Fractional zero denominator", "3/0", odds.Fractional, true},
+		{"American", "-110", odds.American, false},
+		{"American in wrong format", "+150", odds.Decimal, true},
+		{"Non string value", 2.5, odds.Decimal, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(Field("price", tt.value, Odds(tt.format)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if err.Code() != errors.ErrInvalidFormat {
+				t.Errorf("Code() = %v, want %v", err.Code(), errors.ErrInvalidFormat)
+			}
+			if err.Details()["field"] != "price" || err.Details()["format"] != string(tt.format) {
+				t.Errorf("Details() = %v, want field and format", err.Details())
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// Profile selects which fields of a rule set apply to a call. Fields without
+// groups always apply; grouped fields apply only when one of their groups is
+// active. In Partial mode, fields whose value is absent (nil or a nil pointer)
+// are skipped entirely, so Required only fires for values that were sent.
+type Profile struct {
+	Groups  []string
+	Partial bool
+}
+
+func Group(groups ...string) Profile {
+	return Profile{Groups: groups}
+}
+
+func PartialGroup(groups ...string) Profile {
+	return Profile{Groups: groups, Partial: true}
+}
+
+// InGroups returns a copy of the field restricted to the given groups.
+func (f ValidationField) InGroups(groups ...string) ValidationField {
+	f.Groups = append(append([]string(nil), f.Groups...), groups...)
+	return f
+}
+
+// Nested returns a copy of the field whose value is also validated as Struct
+// does: the `validate` tags of struct values and of the structs, pointers and
+// slices they contain, and the Validate methods found along the way.
+func (f ValidationField) Nested() ValidationField {
+	f.Walk = true
+	return f
+}
+
+// Deref returns a copy of the field whose options receive the value a pointer
+// points to, or nil for a nil pointer, so that a *string is validated as its
+// string and a nil pointer fails Required. Options otherwise receive the
+// value as given, as they always have.
+func (f ValidationField) Deref() ValidationField {
+	f.Indirect = true
+	return f
+}
+
+func (p Profile) Validate(fields ...ValidationField) errors.LayerError {
+	return p.ValidateContext(context.Background(), fields...)
+}
+
+// ValidateContext validates the fields and then runs the Validate method of
+// values, or slice elements, implementing Validatable or
+// ValidatableWithContext, with ctx. Fields marked with Nested are walked as
+// Struct walks them.
+func (p Profile) ValidateContext(ctx context.Context, fields ...ValidationField) errors.LayerError {
+	for _, f := range fields {
+		if !p.includes(f) {
+			continue
+		}
+		value, _ := f.normalize()
+		if f.Indirect {
+			value = indirect(value)
+		}
+		if p.Partial && (value == nil || absent(reflect.ValueOf(value))) {
+			continue
+		}
+		for _, opt := range f.Options {
+			if err := opt(f.Field, value); err != nil {
+				return err
+			}
+		}
+		val := reflect.ValueOf(f.Value)
+		if f.Walk {
+			if err := newWalker(ctx, p).nested(f.Field, val); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := validateElements(ctx, f.Field, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p Profile) includes(f ValidationField) bool {
+	return p.active(f.Groups)
+}
+
+// active reports whether rules restricted to groups apply. Rules without
+// groups always apply.
+func (p Profile) active(groups []string) bool {
+	if len(groups) == 0 {
+		return true
+	}
+	for _, g := range groups {
+		for _, active := range p.Groups {
+			if g == active {
+				return true
+			}
+		}
+	}
+	return false
+}
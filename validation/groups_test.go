@@ -0,0 +1,115 @@
+package validation
+
+import (
+	"testing"
+)
+
+type userDTO struct {
+	Email    *string
+	Password *string
+}
+
+func userRules(u userDTO) []ValidationField {
+	return []ValidationField{
+		Field("email", u.Email, Required(), Email()).Deref(),
+		Field("password", u.Password, Required(), MinLength(8)).InGroups("create").Deref(),
+		Field("password", u.Password, MinLength(8)).InGroups("update").Deref(),
+	}
+}
+
+func TestProfile_Groups(t *testing.T) {
+	email := "test@example.com"
+	short := "123"
+
+	tests := []struct {
+		name    string
+		profile Profile
+		dto     userDTO
+		wantErr bool
+	}{
+		{
+			name:    "create requires password",
+			profile: Group("create"),
+			dto:     userDTO{Email: &email},
+			wantErr: true,
+		},
+		{
+			name:    "update validates password when present",
+			profile: Group("update"),
+			dto:     userDTO{Email: &email, Password: &short},
+			wantErr: true,
+		},
+		{
+			name:    "update without password is valid",
+			profile: Group("update"),
+			dto:     userDTO{Email: &email},
+			wantErr: false,
+		},
+		{
+			name:    "partial skips absent required fields",
+			profile: PartialGroup("create"),
+			dto:     userDTO{},
+			wantErr: false,
+		},
+		{
+			name:    "partial still validates present fields",
+			profile: PartialGroup("create"),
+			dto:     userDTO{Password: &short},
+			wantErr: true,
+		},
+		{
+			name:    "no group only runs ungrouped fields",
+			profile: Profile{},
+			dto:     userDTO{Email: &email},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.profile.Validate(userRules(tt.dto)...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type accountDTO struct {
+	Email    *string `json:"email" validate:"required,email"`
+	Password *string `json:"password" validate:"required,min=8,groups=create"`
+	Nickname *string `json:"nickname" validate:"omitempty,min=3,groups=create update"`
+}
+
+func TestProfile_Struct(t *testing.T) {
+	email, short, nick := "test@example.com", "123", "ab"
+
+	tests := []struct {
+		name      string
+		profile   Profile
+		dto       accountDTO
+		wantField any
+	}{
+		{"create requires password", Group("create"), accountDTO{Email: &email}, "password"},
+		{"update skips password rules", Group("update"), accountDTO{Email: &email, Password: &short}, nil},
+		{"update checks shared group", Group("update"), accountDTO{Email: &email, Nickname: &nick}, "nickname"},
+		{"no group only runs ungrouped rules", Profile{}, accountDTO{Email: &email, Nickname: &nick}, nil},
+		{"partial skips absent fields", PartialGroup("create"), accountDTO{}, nil},
+		{"partial validates present fields", PartialGroup("create"), accountDTO{Password: &short}, "password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.profile.Struct(tt.dto)
+			if tt.wantField == nil {
+				if err != nil {
+					t.Fatalf("Struct() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Details()["field"] != tt.wantField {
+				t.Errorf("Struct() = %v, want error on %v", err, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+)
+
+// StakeBetween validates that an amount lies in the inclusive range
+// [min, max] of the currency. Malformed amounts fail with ErrInvalidAmount;
+// amounts out of range fail with the business rule ErrStakeOutOfRange. min
+// and max are parsed eagerly and panic when invalid, like Pattern.
+func StakeBetween(min, max, currency string, msg ...string) ValidationOption {
+	lo, hi := money.MustParse(min, currency), money.MustParse(max, currency)
+	return func(field string, value any) errors.LayerError {
+		message := fmt.Sprintf("Field '%s' must be between %s and %s %s", field, lo, hi, currency)
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		amount, err := amountValue(field, value, currency)
+		if err != nil {
+			return err
+		}
+		if amount.Minor() < lo.Minor() || amount.Minor() > hi.Minor() {
+			return errors.NewBusinessRuleError(errors.ErrStakeOutOfRange, message, map[string]any{
+				"field":    field,
+				"value":    amount.String(),
+				"min":      lo.String(),
+				"max":      hi.String(),
+				"currency": currency,
+			})
+		}
+		return nil
+	}
+}
+
+// MaxPrecision validates that a string amount has no more decimal places than
+// the currency allows (2 for MXN, 0 for JPY).
+func MaxPrecision(currency string, msg ...string) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		exp, _ := money.Exponent(currency)
+		message := fmt.Sprintf("Field '%s' must have at most %d decimal places", field, exp)
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		if str, ok := value.(string); ok && money.Precision(str) > exp {
+			return errors.NewValidationError(errors.ErrInvalidAmount, message, map[string]any{
+				"field":         field,
+				"value":         str,
+				"currency":      currency,
+				"max_precision": exp,
+			})
+		}
+		_, err := amountValue(field, value, currency)
+		return err
+	}
+}
+
+// PositiveAmount validates that an amount is strictly greater than zero.
+func PositiveAmount(currency string, msg ...string) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		message := fmt.Sprintf("Field '%s' must be a positive amount", field)
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		amount, err := amountValue(field, value, currency)
+		if err != nil {
+			return err
+		}
+		if !amount.IsPositive() {
+			return errors.NewValidationError(errors.ErrInvalidAmount, message, map[string]any{
+				"field":    field,
+				"value":    amount.String(),
+				"currency": currency,
+			})
+		}
+		return nil
+	}
+}
+
+// amountValue accepts money.Amount values in the expected currency and plain
+// decimal strings parsed with money.Parse.
+func amountValue(field string, value any, currency string) (money.Amount, errors.LayerError) {
+	switch v := value.(type) {
+	case money.Amount:
+		if v.Currency() != currency {
+			return money.Amount{}, errors.NewValidationError(errors.ErrInvalidCurrency,
+				fmt.Sprintf("Field '%s' must be in %s", field, currency),
+				map[string]any{"field": field, "currency": v.Currency(), "expected_currency": currency})
+		}
+		return v, nil
+	case string:
+		amount, err := money.Parse(v, currency)
+		if err != nil {
+			details := map[string]any{"field": field}
+			for k, val := range err.Details() {
+				details[k] = val
+			}
+			return money.Amount{}, errors.NewValidationError(err.Code(), fmt.Sprintf("Field '%s' must be a valid %s amount", field, currency), details)
+		}
+		return amount, nil
+	}
+	return money.Amount{}, errors.NewValidationError(errors.ErrInvalidAmount,
+		fmt.Sprintf("Field '%s' must be a valid %s amount", field, currency),
+		map[string]any{"field": field, "currency": currency})
+}
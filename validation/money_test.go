@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/money"
+)
+
+func TestMoneyValidators(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		opt      ValidationOption
+		wantCode errors.ErrorCode
+		wantType errors.ErrorType
+	}{
+		{"Stake in range", "100.00", StakeBetween("10", "50000", "MXN"), "", ""},
+		{"Stake at bounds", "10", StakeBetween("10", "50000", "MXN"), "", ""},
+		{"Stake as Amount", money.MustParse("25", "MXN"), StakeBetween("10", "50000", "MXN"), "", ""},
+		{"Stake below min", "9.99", StakeBetween("10", "50000", "MXN"), errors.ErrStakeOutOfRange, errors.BusinessRuleError},
+		{"Stake above max", "50000.01", StakeBetween("10", "50000", "MXN"), errors.ErrStakeOutOfRange, errors.BusinessRuleError},
+		{"Stake malformed", "10,00", StakeBetween("10", "50000", "MXN"), errors.ErrInvalidAmount, errors.ValidationError},
+		{"Stake wrong currency", money.MustParse("25", "USD"), StakeBetween("10", "50000", "MXN"), errors.ErrInvalidCurrency, errors.ValidationError},
+		{"Precision MXN", "10.25", MaxPrecision("MXN"), "", ""},
+		{"Precision MXN exceeded", "10.255", MaxPrecision("MXN"), errors.ErrInvalidAmount, errors.ValidationError},
+		{"Precision JPY exceeded", "1000.5", MaxPrecision("JPY"), errors.ErrInvalidAmount, errors.ValidationError},
+		{"Positive", "0.01", PositiveAmount("MXN"), "", ""},
+		{"Positive rejects zero", "0", PositiveAmount("MXN"), errors.ErrInvalidAmount, errors.ValidationError},
+		{"Positive rejects negative", "-5", PositiveAmount("MXN"), errors.ErrInvalidAmount, errors.ValidationError},
+		{"Float is rejected", 10.5, PositiveAmount("MXN"), errors.ErrInvalidAmount, errors.ValidationError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(Field("stake", tt.value, tt.opt))
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() expected %v, got nil", tt.wantCode)
+			}
+			if err.Code() != tt.wantCode || err.Type() != tt.wantType {
+				t.Errorf("error = %v/%v, want %v/%v", err.Code(), err.Type(), tt.wantCode, tt.wantType)
+			}
+			if err.Details()["field"] != "stake" {
+				t.Errorf("Details[field] = %v, want stake", err.Details()["field"])
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package validation
+
+import (
+	_ "embed"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed unicode_nfc.txt
+var unicodeNFC string
+
+// nfcTables holds the Unicode data needed for canonical composition: the
+// combining class of every non-starter, the full canonical decomposition of
+// every decomposable rune and the primary composites keyed by their pair.
+type nfcTables struct {
+	combining map[rune]uint8
+	decompose map[rune][]rune
+	compose   map[[2]rune]rune
+}
+
+var nfcData = parseNFC(unicodeNFC)
+
+func parseNFC(data string) nfcTables {
+	t := nfcTables{combining: map[rune]uint8{}, decompose: map[rune][]rune{}, compose: map[[2]rune]rune{}}
+	excluded := map[rune]bool{}
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Split(line, ";")
+		if strings.HasPrefix(line, "#") || len(fields) != 4 {
+			continue
+		}
+		r := hexRune(fields[0])
+		if ccc, _ := strconv.Atoi(fields[1]); ccc != 0 {
+			t.combining[r] = uint8(ccc)
+		}
+		for _, code := range strings.Fields(fields[2]) {
+			t.decompose[r] = append(t.decompose[r], hexRune(code))
+		}
+		excluded[r] = fields[3] == "x"
+	}
+	// Singletons, non-starters and decompositions starting with a
+	// non-starter never compose, which together with the listed exclusions
+	// makes the Full_Composition_Exclusion property.
+	for r, d := range t.decompose {
+		if len(d) == 2 && !excluded[r] && t.combining[r] == 0 && t.combining[d[0]] == 0 {
+			t.compose[[2]rune{d[0], d[1]}] = r
+		}
+	}
+	for r := range t.decompose {
+		t.decompose[r] = t.expand(r)
+	}
+	return t
+}
+
+// expand returns the full canonical decomposition of r, applying the
+// one-level mappings recursively.
+func (t nfcTables) expand(r rune) []rune {
+	d, ok := t.decompose[r]
+	if !ok {
+		return []rune{r}
+	}
+	var out []rune
+	for _, c := range d {
+		out = append(out, t.expand(c)...)
+	}
+	return out
+}
+
+func hexRune(s string) rune {
+	n, _ := strconv.ParseUint(s, 16, 32)
+	return rune(n)
+}
+
+// Hangul syllables are decomposed and composed arithmetically.
+const (
+	hangulBase   = 0xAC00
+	hangulL      = 0x1100
+	hangulV      = 0x1161
+	hangulT      = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulCount  = hangulLCount * hangulNCount
+)
+
+// nfc returns the Normalization Form C of s: its canonical decomposition,
+// with combining marks in canonical order, recomposed.
+func nfc(s string) string {
+	if isLatin1(s) {
+		return s
+	}
+	runes := nfcData.canonicalOrder(nfcData.decomposeAll(s))
+	return string(nfcData.composeAll(runes))
+}
+
+// isLatin1 reports whether s only holds runes below U+0300, which are all
+// starters that never combine with each other, so s is already in NFC.
+func isLatin1(s string) bool {
+	for _, r := range s {
+		if r >= 0x300 {
+			return false
+		}
+	}
+	return true
+}
+
+func (t nfcTables) decomposeAll(s string) []rune {
+	out := make([]rune, 0, len(s))
+	for _, r := range s {
+		if i := r - hangulBase; i >= 0 && i < hangulCount {
+			out = append(out, hangulL+i/hangulNCount, hangulV+i%hangulNCount/hangulTCount)
+			if tail := i % hangulTCount; tail != 0 {
+				out = append(out, hangulT+tail)
+			}
+			continue
+		}
+		if d, ok := t.decompose[r]; ok {
+			out = append(out, d...)
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// canonicalOrder sorts every run of non-starters by combining class, keeping
+// marks of the same class in their original order.
+func (t nfcTables) canonicalOrder(runes []rune) []rune {
+	for start := 0; start < len(runes); start++ {
+		if t.combining[runes[start]] == 0 {
+			continue
+		}
+		end := start
+		for end < len(runes) && t.combining[runes[end]] != 0 {
+			end++
+		}
+		run := runes[start:end]
+		sort.SliceStable(run, func(i, j int) bool { return t.combining[run[i]] < t.combining[run[j]] })
+		start = end
+	}
+	return runes
+}
+
+// composeAll combines each starter with the following runes it composes
+// with, unless a rune of the same or a higher combining class sits between
+// them.
+func (t nfcTables) composeAll(runes []rune) []rune {
+	if len(runes) == 0 {
+		return runes
+	}
+	starter := 0
+	last := int(t.combining[runes[0]])
+	if last != 0 {
+		// No starter yet: nothing before the first starter composes.
+		last = 256
+	}
+	n := 1
+	for _, r := range runes[1:] {
+		class := int(t.combining[r])
+		if c, ok := t.pair(runes[starter], r); ok && (last < class || last == 0) {
+			runes[starter] = c
+			continue
+		}
+		if class == 0 {
+			starter = n
+		}
+		last = class
+		runes[n] = r
+		n++
+	}
+	return runes[:n]
+}
+
+func (t nfcTables) pair(a, b rune) (rune, bool) {
+	if l, v := a-hangulL, b-hangulV; l >= 0 && l < hangulLCount && v >= 0 && v < hangulVCount {
+		return hangulBase + (l*hangulVCount+v)*hangulTCount, true
+	}
+	if s, tail := a-hangulBase, b-hangulT; s >= 0 && s < hangulCount && s%hangulTCount == 0 && tail > 0 && tail < hangulTCount {
+		return a + tail, true
+	}
+	c, ok := t.compose[[2]rune{a, b}]
+	return c, ok
+}
@@ -0,0 +1,31 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/odds"
+)
+
+// Odds validates a string price in the given format. Failures use
+// ErrInvalidFormat and carry the format, value and reason in Details.
+func Odds(format odds.Format, msg ...string) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		message := fmt.Sprintf("Field '%s' must be valid %s odds", field, format)
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		str, ok := value.(string)
+		if !ok {
+			return errors.NewValidationError(errors.ErrInvalidFormat, message, map[string]any{"field": field, "format": string(format)})
+		}
+		if _, err := odds.Parse(format, str); err != nil {
+			details := map[string]any{"field": field}
+			for k, v := range err.Details() {
+				details[k] = v
+			}
+			return errors.NewValidationError(errors.ErrInvalidFormat, message, details)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,210 @@
+package validation
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// Password requirement identifiers reported in Details["requirements"].
+const (
+	PasswordMinLength   = "min_length"
+	PasswordMaxLength   = "max_length"
+	PasswordUpper       = "uppercase"
+	PasswordLower       = "lowercase"
+	PasswordDigit       = "digit"
+	PasswordSymbol      = "symbol"
+	PasswordMaxRepeated = "max_repeated"
+	PasswordCommon      = "not_common"
+	PasswordUserInputs  = "no_user_inputs"
+	PasswordMinEntropy  = "min_entropy"
+)
+
+// PasswordPolicy configures the Password validator. Zero values disable the
+// corresponding requirement.
+type PasswordPolicy struct {
+	MinLength      int
+	MaxLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSymbol  bool
+	MaxRepeated    int     // maximum run of the same character
+	DisallowCommon bool    // reject passwords in the embedded deny list
+	MinEntropy     float64 // minimum estimated entropy in bits
+	UserInputs     []string
+}
+
+// DefaultPasswordPolicy is the policy used for Betmates accounts.
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength:      10,
+	MaxLength:      128,
+	RequireUpper:   true,
+	RequireLower:   true,
+	RequireDigit:   true,
+	MaxRepeated:    3,
+	DisallowCommon: true,
+	MinEntropy:     50,
+}
+
+// WithUserInputs returns a copy of the policy that also rejects passwords
+// containing any of the inputs, such as the username or email. For emails,
+// the local part is checked as well.
+func (p PasswordPolicy) WithUserInputs(inputs ...string) PasswordPolicy {
+	p.UserInputs = append(append([]string(nil), p.UserInputs...), inputs...)
+	return p
+}
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+var commonPasswords = codeSet(commonPasswordList)
+
+// Password validates a password against the policy. Every unmet requirement is
+// listed in Details["requirements"] so clients can render a checklist; the
+// password itself is never included in the error.
+func Password(policy PasswordPolicy, msg ...string) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		message := fmt.Sprintf("Field '%s' does not meet the password policy", field)
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		str, ok := value.(string)
+		if !ok {
+			return errors.NewValidationError(errors.ErrInvalidPassword, message, map[string]any{"field": field})
+		}
+		unmet := policy.check(str)
+		if len(unmet) == 0 {
+			return nil
+		}
+		return errors.NewValidationError(errors.ErrInvalidPassword, message, map[string]any{
+			"field":        field,
+			"requirements": unmet,
+		})
+	}
+}
+
+func (p PasswordPolicy) check(password string) []string {
+	var unmet []string
+	length := utf8.RuneCountInString(password)
+	if p.MinLength > 0 && length < p.MinLength {
+		unmet = append(unmet, PasswordMinLength)
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		unmet = append(unmet, PasswordMaxLength)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		unmet = append(unmet, PasswordUpper)
+	}
+	if p.RequireLower && !lower {
+		unmet = append(unmet, PasswordLower)
+	}
+	if p.RequireDigit && !digit {
+		unmet = append(unmet, PasswordDigit)
+	}
+	if p.RequireSymbol && !symbol {
+		unmet = append(unmet, PasswordSymbol)
+	}
+	if p.MaxRepeated > 0 && longestRun(password) > p.MaxRepeated {
+		unmet = append(unmet, PasswordMaxRepeated)
+	}
+	if p.DisallowCommon && commonPasswords[strings.ToLower(password)] {
+		unmet = append(unmet, PasswordCommon)
+	}
+	if containsUserInput(password, p.UserInputs) {
+		unmet = append(unmet, PasswordUserInputs)
+	}
+	if p.MinEntropy > 0 && Entropy(password) < p.MinEntropy {
+		unmet = append(unmet, PasswordMinEntropy)
+	}
+	return unmet
+}
+
+// Entropy estimates the entropy of a password in bits as its length times the
+// log2 of the character pool implied by the classes it uses. It overestimates
+// dictionary words, which is why the deny list is checked separately.
+func Entropy(password string) float64 {
+	var pool int
+	var upper, lower, digit, symbol, other bool
+	for _, r := range password {
+		switch {
+		case r > unicode.MaxASCII:
+			other = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{upper, 26}, {lower, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.used {
+			pool += class.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+	return float64(utf8.RuneCountInString(password)) * math.Log2(float64(pool))
+}
+
+func longestRun(s string) int {
+	longest, run := 0, 0
+	var prev rune = -1
+	for _, r := range s {
+		if r == prev {
+			run++
+		} else {
+			run = 1
+			prev = r
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	return longest
+}
+
+// containsUserInput ignores inputs shorter than three characters, which would
+// reject too many passwords by accident.
+func containsUserInput(password string, inputs []string) bool {
+	lowered := strings.ToLower(password)
+	for _, input := range inputs {
+		candidates := []string{input}
+		if at := strings.LastIndex(input, "@"); at > 0 {
+			candidates = append(candidates, input[:at])
+		}
+		for _, c := range candidates {
+			c = strings.ToLower(strings.TrimSpace(c))
+			if utf8.RuneCountInString(c) >= 3 && strings.Contains(lowered, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+func TestPassword(t *testing.T) {
+	policy := DefaultPasswordPolicy.WithUserInputs("jdoe", "john.doe@example.com")
+
+	tests := []struct {
+		name     string
+		password string
+		policy   PasswordPolicy
+		want     []string
+	}{
+		{"Strong password", "Tr1via-Goleada", policy, nil},
+		{"Too short", "Ab1", policy, []string{PasswordMinLength, PasswordMinEntropy}},
+		{"Missing classes", "golesgolesgoles", policy, []string{PasswordUpper, PasswordDigit}},
+		{"Repeated characters", "Goooool2024mx", policy, []string{PasswordMaxRepeated}},
+		{"Common password", "Password123", PasswordPolicy{DisallowCommon: true}, []string{PasswordCommon}},
+		{"Contains username", "Jdoe-Apuesta-9", policy, []string{PasswordUserInputs}},
+		{"Contains email local part", "xJohn.Doe2024x", policy, []string{PasswordUserInputs}},
+		{"Symbol required", "Abcdefgh12", PasswordPolicy{RequireSymbol: true}, []string{PasswordSymbol}},
+		{"Too long", "abcdefghijk", PasswordPolicy{MaxLength: 10}, []string{PasswordMaxLength}},
+		{"Zero policy accepts anything", "a", PasswordPolicy{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(Field("password", tt.password, Password(tt.policy)))
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v (%v)", err, err.Details())
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() expected error with %v", tt.want)
+			}
+			if err.Code() != errors.ErrInvalidPassword {
+				t.Errorf("Code() = %v, want %v", err.Code(), errors.ErrInvalidPassword)
+			}
+			if got := err.Details()["requirements"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Details[requirements] = %v, want %v", got, tt.want)
+			}
+			if _, ok := err.Details()["value"]; ok {
+				t.Error("Password must not be included in details")
+			}
+		})
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	tests := []struct {
+		password string
+		min, max float64
+	}{
+		{"", 0, 0},
+		{"aaaa", 18, 19},
+		{"Tr1via-Goleada", 85, 95},
+	}
+	for _, tt := range tests {
+		if got := Entropy(tt.password); got < tt.min || got > tt.max {
+			t.Errorf("Entropy(%q) = %.1f, want between %.0f and %.0f", tt.password, got, tt.min, tt.max)
+		}
+	}
+}
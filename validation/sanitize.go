@@ -0,0 +1,126 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Transformer normalizes a string value before any ValidationOption runs.
+type Transformer func(value string) string
+
+// Transform returns a copy of the field with the given transformers appended
+// to its transform stage. Transformers run in order, before validation.
+func (f ValidationField) Transform(ts ...Transformer) ValidationField {
+	f.Transforms = append(append([]Transformer(nil), f.Transforms...), ts...)
+	return f
+}
+
+// Normalize runs the transform stage of every field, writing the result back
+// when the field value is a *string, and returns the names of the fields whose
+// value changed.
+func Normalize(fields ...ValidationField) []string {
+	var changed []string
+	for _, f := range fields {
+		if _, ok := f.normalize(); ok {
+			changed = append(changed, f.Field)
+		}
+	}
+	return changed
+}
+
+// normalize applies the transformers of a string or non-nil *string field,
+// stores the result back through the pointer and returns it, reporting
+// whether the value changed. Fields without transformers, and other values,
+// are returned as they are.
+func (f ValidationField) normalize() (any, bool) {
+	if len(f.Transforms) == 0 {
+		return f.Value, false
+	}
+	var str string
+	switch v := f.Value.(type) {
+	case string:
+		str = v
+	case *string:
+		if v == nil {
+			return f.Value, false
+		}
+		str = *v
+	default:
+		return f.Value, false
+	}
+	out := str
+	for _, t := range f.Transforms {
+		out = t(out)
+	}
+	if ptr, ok := f.Value.(*string); ok {
+		*ptr = out
+	}
+	return out, out != str
+}
+
+func indirect(value any) any {
+	if value == nil {
+		return nil
+	}
+	val := reflect.ValueOf(value)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	return val.Interface()
+}
+
+func Trim() Transformer {
+	return strings.TrimSpace
+}
+
+func Lower() Transformer {
+	return strings.ToLower
+}
+
+func Upper() Transformer {
+	return strings.ToUpper
+}
+
+func CollapseWhitespace() Transformer {
+	return func(value string) string {
+		return strings.Join(strings.Fields(value), " ")
+	}
+}
+
+func NormalizeEmail() Transformer {
+	return func(value string) string {
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+}
+
+func StripControlChars() Transformer {
+	return func(value string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+				return -1
+			}
+			return r
+		}, value)
+	}
+}
+
+func DigitsOnly() Transformer {
+	return func(value string) string {
+		return strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, value)
+	}
+}
+
+// NormalizeUnicode converts the value to Unicode Normalization Form C, so
+// that a decomposed "e" + U+0301 equals the precomposed "é" in any script.
+func NormalizeUnicode() Transformer {
+	return nfc
+}
@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+func TestTransformers(t *testing.T) {
+	tests := []struct {
+		name  string
+		t     Transformer
+		input string
+		want  string
+	}{
+		{"Trim", Trim(), "  john  ", "john"},
+		{"Lower", Lower(), "JoHn", "john"},
+		{"Upper", Upper(), "mx", "MX"},
+		{"CollapseWhitespace", CollapseWhitespace(), " Juan \t  Pérez\n", "Juan Pérez"},
+		{"NormalizeEmail", NormalizeEmail(), "  John.Doe@Example.COM ", "john.doe@example.com"},
+		{"StripControlChars", StripControlChars(), "ab\x00c\u200bd\x1b", "abcd"},
+		{"DigitsOnly", DigitsOnly(), "+52 (55) 1234-5678", "525512345678"},
+		{"NormalizeUnicode", NormalizeUnicode(), "Jose\u0301 Nun\u0303ez", "José Nuñez"},
+		{"NormalizeUnicode keeps composed", NormalizeUnicode(), "José", "José"},
+		{"NormalizeUnicode Greek", NormalizeUnicode(), "\u0391\u0301", "\u0386"},
+		{"NormalizeUnicode Vietnamese", NormalizeUnicode(), "Vie\u0323\u0302t", "Vi\u1ec7t"},
+		{"NormalizeUnicode reorders marks", NormalizeUnicode(), "a\u0302\u0323", "\u1ead"},
+		{"NormalizeUnicode Hangul", NormalizeUnicode(), "\u1112\u1161\u11ab", "\ud55c"},
+		{"NormalizeUnicode singleton", NormalizeUnicode(), "\u212b", "\u00c5"},
+		{"NormalizeUnicode exclusion", NormalizeUnicode(), "\u0958", "\u0915\u093c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.t(tt.input); got != tt.want {
+				t.Errorf("%s(%q) = %q, want %q", tt.name, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize_WritesBackThroughPointer(t *testing.T) {
+	email := "  John@Example.com "
+	name := "Ana"
+
+	changed := Normalize(
+		Field("email", &email).Transform(NormalizeEmail()),
+		Field("name", &name).Transform(Trim()),
+	)
+
+	if email != "john@example.com" {
+		t.Errorf("email = %q, want %q", email, "john@example.com")
+	}
+	if len(changed) != 1 || changed[0] != "email" {
+		t.Errorf("Normalize() changed = %v, want [email]", changed)
+	}
+}
+
+func TestValidate_TransformsRunBeforeOptions(t *testing.T) {
+	email := "  John@Example.com "
+	err := Validate(Field("email", &email, Required(), Email()).Transform(NormalizeEmail()))
+	if err != nil {
+		t.Errorf("Did not expect error after normalization: %v", err)
+	}
+	if email != "john@example.com" {
+		t.Errorf("email = %q, want %q", email, "john@example.com")
+	}
+
+	value := "   "
+	err = Validate(Field("name", value, Required()).Transform(Trim()))
+	if err == nil {
+		t.Error("Expected error for blank value after trimming")
+	}
+}
+
+// Options receive pointers as given, as they did before transforms were
+// added, unless the field opts in with Deref.
+func TestValidate_Pointers(t *testing.T) {
+	var missing *string
+	if err := Validate(Field("name", missing, Required())); err != nil {
+		t.Errorf("Validate(nil *string) unexpected error: %v", err)
+	}
+	if err := Validate(Field("name", missing, Required()).Deref()); err == nil || err.Code() != errors.ErrMissingRequired {
+		t.Errorf("Validate(nil *string).Deref() = %v, want %s", err, errors.ErrMissingRequired)
+	}
+
+	email := "punter@example.com"
+	if err := Validate(Field("email", &email, Email()).Deref()); err != nil {
+		t.Errorf("Validate(*string).Deref() unexpected error: %v", err)
+	}
+
+	var got any
+	count := 3
+	_ = Validate(Field("count", &count, Custom(func(v any) bool { got = v; return true }, "unused")))
+	if got != &count {
+		t.Errorf("option received %#v, want the *int as given", got)
+	}
+	_ = Validate(Field("count", &count, Custom(func(v any) bool { got = v; return true }, "unused")).Deref())
+	if got != 3 {
+		t.Errorf("option received %#v, want the dereferenced int 3 with Deref", got)
+	}
+}
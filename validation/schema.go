@@ -0,0 +1,390 @@
+package validation
+
+import (
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+	"unsafe"
+	"weak"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// SchemaDialect is the $schema of exported documents.
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema 2020-12 that maps to validation rules.
+// Keywords it does not declare are ignored when parsing.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	PropertyNames        *Schema            `json:"propertyNames,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Not                  *Schema            `json:"not,omitempty"`
+}
+
+// SchemaOf exports the rules of fields as an object schema with one property
+// per field. See Profile.Schema.
+func SchemaOf(fields ...ValidationField) *Schema {
+	return Profile{}.Schema(fields...)
+}
+
+// Schema exports the fields active in the profile as an object schema. The
+// type of each property comes from the field value, Required fields are
+// listed in "required" unless the profile is Partial, and the built-in
+// rules become keywords: MinLength becomes minLength, or minItems for
+// slices, Email becomes format "email", Or becomes anyOf, Each becomes
+// items, and so on. Custom options and rules without a JSON Schema
+// equivalent, such as Password or MinAge, are left out. Optional is exported
+// as anyOf the zero value and its rules.
+func (p Profile) Schema(fields ...ValidationField) *Schema {
+	root := &Schema{Schema: SchemaDialect, Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range fields {
+		if !p.includes(f) {
+			continue
+		}
+		typ := reflect.TypeOf(f.Value)
+		prop, ok := root.Properties[f.Field]
+		if !ok {
+			prop = typeSchema(typ, nil)
+			root.Properties[f.Field] = prop
+		}
+		probe := &schemaProbe{schema: prop, typ: typ}
+		probe.run(f.Options)
+		if probe.required && !p.Partial {
+			root.require(f.Field)
+		}
+	}
+	return root
+}
+
+// StructSchema exports the `validate` tags of the type of v, a struct or a
+// pointer to one, as an object schema. Properties are named after their json
+// tag and nested structs become nested object schemas.
+func StructSchema(v any) *Schema {
+	s := typeSchema(reflect.TypeOf(v), nil)
+	s.Schema = SchemaDialect
+	return s
+}
+
+func (s *Schema) require(name string) {
+	for _, r := range s.Required {
+		if r == name {
+			return
+		}
+	}
+	s.Required = append(s.Required, name)
+	sort.Strings(s.Required)
+}
+
+func (s *Schema) isEmpty() bool {
+	return s == nil || reflect.ValueOf(*s).IsZero()
+}
+
+// merge adds the keywords of frag to s. When a keyword is already set, frag
+// is appended to allOf instead, so both constraints hold.
+func (s *Schema) merge(frag *Schema) {
+	dst, src := reflect.ValueOf(s).Elem(), reflect.ValueOf(frag).Elem()
+	for i := 0; i < src.NumField(); i++ {
+		if !src.Field(i).IsZero() && !dst.Field(i).IsZero() {
+			s.AllOf = append(s.AllOf, frag)
+			return
+		}
+	}
+	for i := 0; i < src.NumField(); i++ {
+		if !src.Field(i).IsZero() {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// typeSchema describes the JSON encoding of t, with the tag rules of struct
+// types. seen stops recursive types.
+func typeSchema(t reflect.Type, seen map[reflect.Type]bool) *Schema {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return &Schema{}
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		s := &Schema{Type: "array"}
+		if items := typeSchema(t.Elem(), seen); !items.isEmpty() {
+			s.Items = items
+		}
+		return s
+	case reflect.Map:
+		s := &Schema{Type: "object"}
+		if values := typeSchema(t.Elem(), seen); !values.isEmpty() {
+			s.AdditionalProperties = values
+		}
+		return s
+	case reflect.Struct:
+		if seen[t] {
+			return &Schema{Type: "object"}
+		}
+		if seen == nil {
+			seen = map[reflect.Type]bool{}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		fields, _ := structFields(t)
+		for _, f := range fields {
+			ft := t.FieldByIndex(f.index).Type
+			prop := typeSchema(ft, seen)
+			probe := &schemaProbe{schema: prop, typ: ft}
+			probe.run(f.opts)
+			if probe.required {
+				s.require(f.name)
+			}
+			s.Properties[f.name] = prop
+		}
+		return s
+	}
+	return &Schema{Type: jsonType(t)}
+}
+
+func jsonType(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return ""
+}
+
+// schemaProbe collects the schema of the options of one value.
+type schemaProbe struct {
+	schema   *Schema
+	typ      reflect.Type
+	required bool
+}
+
+// rule is an option built with describe: the check it runs and its schema.
+type rule struct {
+	check  ValidationOption
+	schema func(p *schemaProbe)
+}
+
+// rules maps the options returned by describe, by closure address, to their
+// rule. The address is unique while the closure is alive, and the closure is
+// the only strong reference to its rule, so a rule that is still alive always
+// belongs to the closure at that address. A cleanup drops the entry once the
+// rule is collected.
+var rules sync.Map // uintptr -> weak.Pointer[rule]
+
+// describe attaches a schema description to opt.
+func describe(opt ValidationOption, fn func(p *schemaProbe)) ValidationOption {
+	r := &rule{check: opt, schema: fn}
+	described := func(field string, value any) errors.LayerError {
+		return r.check(field, value)
+	}
+	key, w := closure(described), weak.Make(r)
+	rules.Store(key, w)
+	runtime.AddCleanup(r, func(key uintptr) { rules.CompareAndDelete(key, w) }, key)
+	return described
+}
+
+// describedRule returns the rule of an option built with describe, or nil.
+func describedRule(opt ValidationOption) *rule {
+	if opt == nil {
+		return nil
+	}
+	w, ok := rules.Load(closure(opt))
+	if !ok {
+		return nil
+	}
+	return w.(weak.Pointer[rule]).Value()
+}
+
+// closure returns the address of the closure behind fn.
+func closure(fn ValidationOption) uintptr {
+	return uintptr(*(*unsafe.Pointer)(unsafe.Pointer(&fn)))
+}
+
+// run probes the options built with describe. Other options, such as Custom
+// functions, are skipped without being called.
+func (p *schemaProbe) run(opts []ValidationOption) {
+	for _, opt := range opts {
+		if r := describedRule(opt); r != nil {
+			r.schema(p)
+		}
+	}
+}
+
+// child probes opts against a fresh schema for a value of type typ.
+func (p *schemaProbe) child(typ reflect.Type, opts ...ValidationOption) *Schema {
+	c := &schemaProbe{schema: &Schema{}, typ: typ}
+	c.run(opts)
+	return c.schema
+}
+
+// into probes opts into *target, a nested schema such as items, for a value
+// of type typ.
+func (p *schemaProbe) into(target **Schema, typ reflect.Type, opts []ValidationOption) {
+	if *target == nil {
+		*target = &Schema{}
+	}
+	(&schemaProbe{schema: *target, typ: typ}).run(opts)
+	if (*target).isEmpty() {
+		*target = nil
+	}
+}
+
+// key returns the key type of a map value.
+func (p *schemaProbe) key() reflect.Type {
+	t := p.typ
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Map {
+		return nil
+	}
+	return t.Key()
+}
+
+func (p *schemaProbe) elem() reflect.Type {
+	t := p.typ
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return t.Elem()
+	}
+	return nil
+}
+
+// length describes a length bound, which applies to strings, arrays or
+// objects depending on the value type.
+func (p *schemaProbe) length(n int, min bool) {
+	switch jsonType(p.typ) {
+	case "array":
+		if min {
+			p.schema.merge(&Schema{MinItems: &n})
+		} else {
+			p.schema.merge(&Schema{MaxItems: &n})
+		}
+	case "object":
+		if min {
+			p.schema.merge(&Schema{MinProperties: &n})
+		} else {
+			p.schema.merge(&Schema{MaxProperties: &n})
+		}
+	default:
+		if min {
+			p.schema.merge(&Schema{MinLength: &n})
+		} else {
+			p.schema.merge(&Schema{MaxLength: &n})
+		}
+	}
+}
+
+// bound describes the min and max tag rules: a value bound for numbers and a
+// length bound otherwise.
+func (p *schemaProbe) bound(limit float64, min bool) {
+	switch jsonType(p.typ) {
+	case "integer", "number":
+		if min {
+			p.schema.merge(&Schema{Minimum: &limit})
+		} else {
+			p.schema.merge(&Schema{Maximum: &limit})
+		}
+	default:
+		p.length(int(limit), min)
+	}
+}
+
+// enum describes a set of allowed values written as strings, converting them
+// to numbers for numeric fields.
+func (p *schemaProbe) enum(values []string) {
+	numeric := jsonType(p.typ) == "integer" || jsonType(p.typ) == "number"
+	enum := make([]any, 0, len(values))
+	for _, v := range values {
+		if n, err := strconv.ParseFloat(v, 64); numeric && err == nil {
+			enum = append(enum, n)
+			continue
+		}
+		enum = append(enum, v)
+	}
+	p.schema.merge(&Schema{Enum: enum})
+}
+
+// zero returns the JSON zero value of a scalar type, or nil.
+func (p *schemaProbe) zero() any {
+	switch jsonType(p.typ) {
+	case "string":
+		return ""
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	}
+	return nil
+}
+
+func stringEnum(values []string) []any {
+	enum := make([]any, len(values))
+	for i, v := range values {
+		enum[i] = v
+	}
+	return enum
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
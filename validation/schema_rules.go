@@ -0,0 +1,426 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// annotations are keywords that do not constrain values, so ParseSchema
+// accepts and ignores them.
+var annotations = map[string]bool{
+	"$id": true, "$comment": true, "title": true, "description": true,
+	"default": true, "examples": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// keywords are the json names of the Schema fields.
+var keywords = func() map[string]bool {
+	names := map[string]bool{}
+	t := reflect.TypeOf(Schema{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		names[name] = true
+	}
+	return names
+}()
+
+// schemaTypes are the values of the type keyword.
+var schemaTypes = map[string]bool{
+	"string": true, "number": true, "integer": true, "boolean": true, "array": true, "object": true, "null": true,
+}
+
+// ParseSchema decodes a JSON Schema document and checks that its patterns
+// compile. Annotations such as title or $id are ignored, and any other
+// keyword Schema does not declare, such as exclusiveMinimum, const, oneOf or
+// $ref, is rejected with ErrInvalidFormat rather than silently dropped, as
+// is a type other than a single type name.
+func ParseSchema(data []byte) (*Schema, errors.LayerError) {
+	if err := checkKeywords(data, "#"); err != nil {
+		return nil, err
+	}
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, errors.NewValidationError(errors.ErrInvalidFormat, "Invalid JSON Schema",
+			map[string]any{"reason": err.Error()})
+	}
+	if err := s.check("#"); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// checkKeywords rejects the keywords of data, a schema at path, and of its
+// subschemas that Schema does not support. Malformed JSON is left to the
+// decoder.
+func checkKeywords(data json.RawMessage, path string) errors.LayerError {
+	var raw map[string]json.RawMessage
+	if json.Unmarshal(data, &raw) != nil {
+		return nil
+	}
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := raw[name]
+		switch {
+		case annotations[name]:
+			continue
+		case !keywords[name]:
+			return unsupportedKeyword(path, name)
+		case name == "type":
+			var typ string
+			if json.Unmarshal(value, &typ) != nil || !schemaTypes[typ] {
+				return errors.NewValidationError(errors.ErrInvalidFormat,
+					fmt.Sprintf("Unsupported type at %s, want a single type name", path),
+					map[string]any{"path": path, "keyword": name, "value": string(value)})
+			}
+		case name == "properties":
+			var props map[string]json.RawMessage
+			_ = json.Unmarshal(value, &props)
+			for _, prop := range sortedKeys(keySet(props)) {
+				if err := checkKeywords(props[prop], path+"/properties/"+prop); err != nil {
+					return err
+				}
+			}
+		case name == "allOf" || name == "anyOf":
+			var subs []json.RawMessage
+			_ = json.Unmarshal(value, &subs)
+			for i, sub := range subs {
+				if err := checkKeywords(sub, fmt.Sprintf("%s/%s/%d", path, name, i)); err != nil {
+					return err
+				}
+			}
+		case name == "items" || name == "additionalProperties" || name == "propertyNames" || name == "not":
+			if err := checkKeywords(value, path+"/"+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func unsupportedKeyword(path, name string) errors.LayerError {
+	return errors.NewValidationError(errors.ErrInvalidFormat,
+		fmt.Sprintf("Unsupported JSON Schema keyword '%s' at %s", name, path),
+		map[string]any{"path": path, "keyword": name})
+}
+
+func keySet[V any](m map[string]V) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for k := range m {
+		set[k] = true
+	}
+	return set
+}
+
+func (s *Schema) check(path string) errors.LayerError {
+	if s == nil {
+		return nil
+	}
+	if s.Pattern != "" {
+		if _, err := regexp.Compile(s.Pattern); err != nil {
+			return errors.NewValidationError(errors.ErrInvalidFormat,
+				fmt.Sprintf("Invalid pattern at %s", path),
+				map[string]any{"path": path, "pattern": s.Pattern, "reason": err.Error()})
+		}
+	}
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := s.Properties[name].check(path + "/properties/" + name); err != nil {
+			return err
+		}
+	}
+	for _, sub := range []struct {
+		key    string
+		schema *Schema
+	}{
+		{"items", s.Items},
+		{"additionalProperties", s.AdditionalProperties},
+		{"propertyNames", s.PropertyNames},
+		{"not", s.Not},
+	} {
+		if err := sub.schema.check(path + "/" + sub.key); err != nil {
+			return err
+		}
+	}
+	for i, sub := range s.AllOf {
+		if err := sub.check(fmt.Sprintf("%s/allOf/%d", path, i)); err != nil {
+			return err
+		}
+	}
+	for i, sub := range s.AnyOf {
+		if err := sub.check(fmt.Sprintf("%s/anyOf/%d", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate validates a decoded JSON object, such as a dynamically configured
+// form submission, against an object schema.
+func (s *Schema) Validate(values map[string]any) errors.LayerError {
+	return Validate(s.Fields(values)...)
+}
+
+// Fields builds one ValidationField per property of an object schema, and
+// one per value not covered by properties when additionalProperties is set.
+// Required properties get Required; the other rules only run when the value
+// is present and not null.
+func (s *Schema) Fields(values map[string]any) []ValidationField {
+	names := map[string]bool{}
+	for name := range s.Properties {
+		names[name] = true
+	}
+	required := map[string]bool{}
+	for _, name := range s.Required {
+		names[name] = true
+		required[name] = true
+	}
+	var fields []ValidationField
+	for _, name := range sortedKeys(names) {
+		var opts []ValidationOption
+		if required[name] {
+			opts = append(opts, Required())
+		}
+		if v, ok := values[name]; ok && v != nil {
+			opts = append(opts, s.Properties[name].Options()...)
+		}
+		fields = append(fields, Field(name, values[name], opts...))
+	}
+	if s.AdditionalProperties != nil {
+		extra := map[string]bool{}
+		for name := range values {
+			if !names[name] {
+				extra[name] = true
+			}
+		}
+		for _, name := range sortedKeys(extra) {
+			fields = append(fields, Field(name, values[name], s.AdditionalProperties.Options()...))
+		}
+	}
+	return fields
+}
+
+// Options builds the rules of a schema for a single value. As in JSON
+// Schema, keywords that do not apply to the value's type are skipped: a
+// minLength does not reject a number, only "type" does. Lengths are counted
+// in characters and enum values are compared with their JSON type, so 1
+// does not match "1", and patterns use Go regexp syntax. Unknown
+// formats are ignored, as JSON Schema treats them as annotations; parse
+// documents with ParseSchema so unsupported keywords are rejected.
+func (s *Schema) Options() []ValidationOption {
+	if s == nil {
+		return nil
+	}
+	var opts []ValidationOption
+	when := func(kind string, opt ValidationOption) {
+		opts = append(opts, ofType(kind, opt))
+	}
+	if s.Type != "" {
+		opts = append(opts, typeRule(s.Type))
+	}
+	if s.MinLength != nil {
+		when("string", runeLength(*s.MinLength, true))
+	}
+	if s.MaxLength != nil {
+		when("string", runeLength(*s.MaxLength, false))
+	}
+	if s.Pattern != "" {
+		when("string", Pattern(s.Pattern))
+	}
+	if rule := schemaFormat(s.Format); rule != nil {
+		when("string", rule)
+	}
+	if s.ContentEncoding == "base64" {
+		when("string", Base64())
+	}
+	if s.Minimum != nil {
+		when("number", bound(*s.Minimum, true))
+	}
+	if s.Maximum != nil {
+		when("number", bound(*s.Maximum, false))
+	}
+	if len(s.Enum) > 0 {
+		opts = append(opts, enumRule(s.Enum))
+	}
+	if s.MinItems != nil {
+		when("array", MinLength(*s.MinItems))
+	}
+	if s.MaxItems != nil {
+		when("array", MaxLength(*s.MaxItems))
+	}
+	if s.Items != nil {
+		when("array", Each(s.Items.Options()...))
+	}
+	if s.MinProperties != nil {
+		when("object", bound(float64(*s.MinProperties), true))
+	}
+	if s.MaxProperties != nil {
+		when("object", bound(float64(*s.MaxProperties), false))
+	}
+	if s.Properties != nil || len(s.Required) > 0 || s.AdditionalProperties != nil {
+		when("object", objectRule(s))
+	}
+	if s.PropertyNames != nil {
+		when("object", Keys(s.PropertyNames.Options()...))
+	}
+	for _, sub := range s.AllOf {
+		opts = append(opts, And(sub.Options()...))
+	}
+	if len(s.AnyOf) > 0 {
+		anyOf := make([]ValidationOption, len(s.AnyOf))
+		for i, sub := range s.AnyOf {
+			anyOf[i] = And(sub.Options()...)
+		}
+		opts = append(opts, Or(anyOf...))
+	}
+	if s.Not != nil {
+		opts = append(opts, Not(And(s.Not.Options()...)))
+	}
+	return opts
+}
+
+// schemaFormat returns the rule for a standard JSON Schema format, or nil.
+func schemaFormat(name string) ValidationOption {
+	switch name {
+	case "email":
+		return Email()
+	case "uuid":
+		return UUID()
+	case "uri":
+		return URL()
+	case "ipv4":
+		return IPv4()
+	case "ipv6":
+		return IPv6()
+	case "date-time":
+		return TimeFormat(time.RFC3339)
+	case "date":
+		return TimeFormat(time.DateOnly)
+	}
+	return nil
+}
+
+// runeLength bounds the length of a string in characters, as JSON Schema
+// counts minLength and maxLength.
+func runeLength(n int, min bool) ValidationOption {
+	return describe(func(field string, value any) errors.LayerError {
+		str, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		count := utf8.RuneCountInString(str)
+		if (min && count >= n) || (!min && count <= n) {
+			return nil
+		}
+		key, word := "max", "at most"
+		if min {
+			key, word = "min", "at least"
+		}
+		return errors.NewValidationError(errors.ErrInvalidFormat,
+			fmt.Sprintf("Field '%s' must have %s %d characters", field, word, n),
+			map[string]any{"field": field, key: n})
+	}, func(p *schemaProbe) { p.length(n, min) })
+}
+
+// enumRule accepts the values equal to one of allowed as JSON values: of the
+// same type, with numbers compared by value.
+func enumRule(allowed []any) ValidationOption {
+	return describe(func(field string, value any) errors.LayerError {
+		for _, a := range allowed {
+			if sameJSON(value, a) {
+				return nil
+			}
+		}
+		names := make([]string, len(allowed))
+		for i, a := range allowed {
+			names[i] = fmt.Sprint(a)
+		}
+		return errors.NewValidationError(errors.ErrInvalidFormat,
+			fmt.Sprintf("Field '%s' must be one of: %s", field, strings.Join(names, ", ")),
+			map[string]any{"field": field, "allowed": allowed})
+	}, func(p *schemaProbe) { p.schema.merge(&Schema{Enum: allowed}) })
+}
+
+func sameJSON(a, b any) bool {
+	x, xok := jsonNumber(a)
+	y, yok := jsonNumber(b)
+	if xok || yok {
+		return xok && yok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func jsonNumber(v any) (float64, bool) {
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	}
+	return 0, false
+}
+
+// objectRule validates a nested object, reporting its fields under the
+// object's path.
+func objectRule(s *Schema) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		values, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		return prefixError(field, Validate(s.Fields(values)...))
+	}
+}
+
+func typeRule(want string) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		got := valueType(value)
+		if value == nil || got == want || (want == "number" && got == "integer") {
+			return nil
+		}
+		return errors.NewValidationError(errors.ErrInvalidFormat,
+			fmt.Sprintf("Field '%s' must be of type %s", field, want),
+			map[string]any{"field": field, "type": want})
+	}
+}
+
+// ofType runs opt only for values of the given JSON type.
+func ofType(kind string, opt ValidationOption) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		got := valueType(value)
+		if got != kind && !(kind == "number" && got == "integer") {
+			return nil
+		}
+		return opt(field, value)
+	}
+}
+
+// valueType is the JSON type of a decoded value. Integral floats, which
+// encoding/json produces for every number, count as integers.
+func valueType(value any) string {
+	if f, ok := value.(float64); ok && f == math.Trunc(f) && !math.IsInf(f, 0) {
+		return "integer"
+	}
+	if value == nil {
+		return ""
+	}
+	return jsonType(reflect.TypeOf(value))
+}
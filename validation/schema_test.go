@@ -0,0 +1,280 @@
+package validation
+
+import (
+	"encoding/json"
+	"runtime"
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+func signupRules(v map[string]any) []ValidationField {
+	return []ValidationField{
+		Field("email", v["email"], Required(), Email()),
+		Field("nickname", v["nickname"], Optional(MinLength(3), MaxLength(20), Pattern(`^[a-z0-9_]+$`))),
+		Field("country", v["country"], Required(), CountryISO3166()),
+		Field("tags", v["tags"], Optional(Each(Slug()), MaxLength(3))),
+		Field("contact", v["contact"], Optional(Or(Email(), PhoneE164()))),
+	}
+}
+
+var signupSample = map[string]any{"email": "", "nickname": "", "country": "", "tags": []any{}, "contact": ""}
+
+// roundTrip exports a schema, encodes it and parses it back.
+func roundTrip(t *testing.T, s *Schema) *Schema {
+	t.Helper()
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	parsed, perr := ParseSchema(data)
+	if perr != nil {
+		t.Fatalf("ParseSchema(%s) error: %v", data, perr)
+	}
+	return parsed
+}
+
+func sameOutcome(t *testing.T, original, imported errors.LayerError) {
+	t.Helper()
+	if (original == nil) != (imported == nil) {
+		t.Fatalf("original = %v, imported = %v", original, imported)
+	}
+	if original != nil && original.Details()["field"] != imported.Details()["field"] {
+		t.Errorf("field: original = %v, imported = %v", original.Details()["field"], imported.Details()["field"])
+	}
+}
+
+func TestSchemaOf_Export(t *testing.T) {
+	s := SchemaOf(signupRules(signupSample)...)
+	if s.Schema != SchemaDialect || s.Type != "object" {
+		t.Errorf("root = %s %s, want an object schema with $schema", s.Schema, s.Type)
+	}
+	if len(s.Required) != 2 || s.Required[0] != "country" || s.Required[1] != "email" {
+		t.Errorf("Required = %v, want [country email]", s.Required)
+	}
+	email := s.Properties["email"]
+	if email.Type != "string" || email.Format != "email" {
+		t.Errorf("email = %+v, want string with format email", email)
+	}
+	tags := s.Properties["tags"]
+	if tags.Type != "array" || tags.MaxItems == nil || *tags.MaxItems != 3 || tags.Items == nil || tags.Items.Pattern == "" {
+		t.Errorf("tags = %+v, want array with maxItems 3 and a slug pattern on items", tags)
+	}
+	nickname := s.Properties["nickname"]
+	if len(nickname.AnyOf) != 2 || nickname.AnyOf[1].MinLength == nil || *nickname.AnyOf[1].MinLength != 3 {
+		t.Errorf("nickname = %+v, want anyOf the empty string and the length rules", nickname)
+	}
+	if len(s.Properties["country"].Enum) < 200 {
+		t.Errorf("country enum has %d codes", len(s.Properties["country"].Enum))
+	}
+}
+
+func TestSchemaOf_Formats(t *testing.T) {
+	s := SchemaOf(Field("allow_list", "", CIDR()))
+	if s.Properties["allow_list"].Pattern == "" {
+		t.Errorf("allow_list = %+v, want a CIDR pattern", s.Properties["allow_list"])
+	}
+}
+
+func TestSchemaOf_Partial(t *testing.T) {
+	s := PartialGroup().Schema(signupRules(signupSample)...)
+	if len(s.Required) != 0 {
+		t.Errorf("Required = %v, want none in partial mode", s.Required)
+	}
+}
+
+func TestSchemaOf_RoundTrip(t *testing.T) {
+	schema := roundTrip(t, SchemaOf(signupRules(signupSample)...))
+
+	tests := []struct {
+		name   string
+		values map[string]any
+	}{
+		{"Valid", map[string]any{"email": "punter@example.com", "country": "MX", "nickname": "lucky_7", "tags": []any{"high-roller"}, "contact": "+525512345678"}},
+		{"Missing email", map[string]any{"country": "MX"}},
+		{"Bad email", map[string]any{"email": "punter", "country": "MX"}},
+		{"Short nickname", map[string]any{"email": "a@b.co", "country": "MX", "nickname": "ab"}},
+		{"Empty nickname", map[string]any{"email": "a@b.co", "country": "MX", "nickname": ""}},
+		{"Nickname pattern", map[string]any{"email": "a@b.co", "country": "MX", "nickname": "Bad!"}},
+		{"Unknown country", map[string]any{"email": "a@b.co", "country": "XX"}},
+		{"Bad tag", map[string]any{"email": "a@b.co", "country": "MX", "tags": []any{"ok-tag", "Bad Tag"}}},
+		{"Too many tags", map[string]any{"email": "a@b.co", "country": "MX", "tags": []any{"a", "b", "c", "d"}}},
+		{"Contact email", map[string]any{"email": "a@b.co", "country": "MX", "contact": "c@d.co"}},
+		{"Bad contact", map[string]any{"email": "a@b.co", "country": "MX", "contact": "nope"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sameOutcome(t, Validate(signupRules(tt.values)...), schema.Validate(tt.values))
+		})
+	}
+}
+
+func TestStructSchema_RoundTrip(t *testing.T) {
+	schema := roundTrip(t, StructSchema(&betSlipDTO{}))
+
+	if schema.Properties["stake"].Minimum == nil || *schema.Properties["stake"].Minimum != 1 {
+		t.Errorf("stake = %+v, want minimum 1", schema.Properties["stake"])
+	}
+	if _, ok := schema.Properties["Ignored"]; ok {
+		t.Error("fields tagged json:\"-\" must not be exported")
+	}
+	if schema.Properties["channel"] == nil {
+		t.Error("embedded struct fields must be flattened")
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(s *betSlipDTO)
+	}{
+		{"Valid", func(s *betSlipDTO) {}},
+		{"Missing email", func(s *betSlipDTO) { s.Email = "" }},
+		{"Currency omitted", func(s *betSlipDTO) { s.Currency = "" }},
+		{"Unknown currency", func(s *betSlipDTO) { s.Currency = "XYZ" }},
+		{"Stake below min", func(s *betSlipDTO) { s.Stake = 0 }},
+		{"Stake above max", func(s *betSlipDTO) { s.Stake = 10001 }},
+		{"Channel", func(s *betSlipDTO) { s.Channel = "fax" }},
+		{"No selections", func(s *betSlipDTO) { s.Selections = nil }},
+		{"Nested uuid", func(s *betSlipDTO) { s.Selections[0].MarketID = "abc" }},
+		{"Nested odds", func(s *betSlipDTO) { s.Selections[0].Odds = 1 }},
+		{"Too many tags", func(s *betSlipDTO) { s.Tags = map[string]string{"a": "1", "b": "2"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slip := validSlip()
+			tt.mutate(&slip)
+			data, _ := json.Marshal(slip)
+			var values map[string]any
+			if err := json.Unmarshal(data, &values); err != nil {
+				t.Fatal(err)
+			}
+			sameOutcome(t, Struct(&slip), schema.Validate(values))
+		})
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	doc := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Deposit form",
+		"type": "object",
+		"required": ["amount"],
+		"properties": {
+			"amount": {"type": "integer", "minimum": 10, "maximum": 5000},
+			"method": {"enum": ["card", "spei"]},
+			"tier": {"enum": [1, 2, true]},
+			"reference": {"type": "string", "maxLength": 8, "format": "x-unknown"},
+			"billing": {
+				"type": "object",
+				"required": ["zip"],
+				"properties": {"zip": {"type": "string", "pattern": "^[0-9]{5}$"}}
+			}
+		}
+	}`
+	schema, err := ParseSchema([]byte(doc))
+	if err != nil {
+		t.Fatalf("ParseSchema() error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		values    map[string]any
+		wantCode  errors.ErrorCode
+		wantField string
+	}{
+		{"Valid", map[string]any{"amount": 100.0, "method": "spei", "billing": map[string]any{"zip": "06600"}}, "", ""},
+		{"Missing amount", map[string]any{}, errors.ErrMissingRequired, "amount"},
+		{"Wrong type", map[string]any{"amount": "100"}, errors.ErrInvalidFormat, "amount"},
+		{"Not an integer", map[string]any{"amount": 10.5}, errors.ErrInvalidFormat, "amount"},
+		{"Below minimum", map[string]any{"amount": 5.0}, errors.ErrInvalidFormat, "amount"},
+		{"Enum", map[string]any{"amount": 100.0, "method": "cash"}, errors.ErrInvalidFormat, "method"},
+		{"Enum number", map[string]any{"amount": 100.0, "tier": 2.0}, "", ""},
+		{"Enum keeps types", map[string]any{"amount": 100.0, "tier": "1"}, errors.ErrInvalidFormat, "tier"},
+		{"Enum boolean", map[string]any{"amount": 100.0, "tier": "true"}, errors.ErrInvalidFormat, "tier"},
+		{"Length in characters", map[string]any{"amount": 100.0, "reference": "ñandú-01"}, "", ""},
+		{"Above max length", map[string]any{"amount": 100.0, "reference": "ñandú-012"}, errors.ErrInvalidFormat, "reference"},
+		{"Unknown format ignored", map[string]any{"amount": 100.0, "reference": "abc"}, "", ""},
+		{"Nested required", map[string]any{"amount": 100.0, "billing": map[string]any{}}, errors.ErrMissingRequired, "billing.zip"},
+		{"Nested pattern", map[string]any{"amount": 100.0, "billing": map[string]any{"zip": "1"}}, errors.ErrInvalidFormat, "billing.zip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate(tt.values)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() expected %s, got nil", tt.wantCode)
+			}
+			if err.Code() != tt.wantCode || err.Details()["field"] != tt.wantField {
+				t.Errorf("Validate() = %s on %v, want %s on %s", err.Code(), err.Details()["field"], tt.wantCode, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestParseSchema_Annotations(t *testing.T) {
+	doc := `{"$id": "bet", "title": "Bet", "description": "A bet", "properties": {"stake": {"type": "number", "examples": [10]}}}`
+	if _, err := ParseSchema([]byte(doc)); err != nil {
+		t.Errorf("ParseSchema() unexpected error: %v", err)
+	}
+}
+
+func TestSchemaOf_SkipsOpaqueOptions(t *testing.T) {
+	called := false
+	opaque := func(field string, value any) errors.LayerError {
+		called = true
+		return nil
+	}
+	schema := SchemaOf(Field("name", "", Required(), opaque, Custom(func(any) bool { panic("probed") }, "x")))
+	if called {
+		t.Error("SchemaOf() called an option not built with describe")
+	}
+	if len(schema.Required) != 1 {
+		t.Errorf("Required = %v, want [name]", schema.Required)
+	}
+}
+
+func TestSchemaOf_AfterCollection(t *testing.T) {
+	opts := []ValidationOption{MinLength(2), MaxLength(8)}
+	for i := 0; i < 1000; i++ {
+		_ = Email()
+	}
+	runtime.GC()
+	runtime.GC()
+	prop := SchemaOf(Field("name", "", opts...)).Properties["name"]
+	if prop.MinLength == nil || *prop.MinLength != 2 || prop.MaxLength == nil || *prop.MaxLength != 8 || prop.Format != "" {
+		t.Errorf("Properties[name] = %+v, want minLength 2 and maxLength 8 only", prop)
+	}
+}
+
+func TestParseSchema_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"Invalid JSON", `{"type": `},
+		{"Invalid pattern", `{"properties": {"zip": {"pattern": "([0-9]"}}}`},
+		{"Exclusive minimum", `{"properties": {"age": {"type": "integer", "exclusiveMinimum": 18}}}`},
+		{"Multiple of", `{"properties": {"stake": {"multipleOf": 0.5}}}`},
+		{"Const", `{"const": "a"}`},
+		{"One of", `{"oneOf": [{"type": "string"}, {"type": "integer"}]}`},
+		{"Reference", `{"properties": {"billing": {"$ref": "#/$defs/address"}}}`},
+		{"Nested in items", `{"items": {"anyOf": [{"uniqueItems": true}]}}`},
+		{"Array type", `{"type": ["string", "null"]}`},
+		{"Unknown type", `{"type": "text"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSchema([]byte(tt.doc)); err == nil || err.Code() != errors.ErrInvalidFormat {
+				t.Errorf("ParseSchema() error = %v, want %s", err, errors.ErrInvalidFormat)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// Struct validates v, a struct or a pointer to one, against the rules in the
+// `validate` tags of its fields and returns the first error found. Fields are
+// named after their json tag, and nested structs, pointers to structs and
+// slices of structs are validated too, so errors point at paths such as
+// "selections[1].odds". Each pointer is followed once, so cyclic values are
+// safe. Nested values implementing Validatable are then validated by their
+// own method; the Validate method of v itself is not called, so it may call
+// Struct. Values that are not structs are valid.
+//
+// Rules are comma separated: required, omitempty, email, uuid, url, e164,
+// country, currency, ip, ipv4, ipv6, cidr, slug, base64, hex, min=N, max=N
+// and oneof=a b c. min and max bound the length of strings, slices and maps
+// and the value of numbers. After omitempty, the remaining rules only run
+// when the value is not zero. groups=a b restricts the rules of the field to
+// those groups, as InGroups does; see Profile.Struct. Fields reflection
+// cannot read are skipped rather than validated as nil. A tag with an unknown
+// or malformed rule fails every validation of its type with an ErrInternal
+// internal error, whose cause names the field and the rule.
+func Struct(v any) errors.LayerError {
+	return Profile{}.StructContext(context.Background(), v)
+}
+
+// StructContext is Struct with a context for ValidatableWithContext values.
+func StructContext(ctx context.Context, v any) errors.LayerError {
+	return Profile{}.StructContext(ctx, v)
+}
+
+// Struct validates v as the package-level Struct does, applying the profile
+// to the `validate` tags: fields tagged with groups only apply when one of
+// their groups is active, and a Partial profile skips fields whose value is
+// absent, a nil pointer, interface, slice or map.
+func (p Profile) Struct(v any) errors.LayerError {
+	return p.StructContext(context.Background(), v)
+}
+
+// StructContext is Profile.Struct with a context for ValidatableWithContext
+// values.
+func (p Profile) StructContext(ctx context.Context, v any) errors.LayerError {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+	return newWalker(ctx, p).structure("", val)
+}
+
+type structField struct {
+	index  []int
+	name   string
+	opts   []ValidationOption
+	groups []string
+}
+
+// structInfo is the parsed form of a struct type. err is the first invalid
+// tag; fields still lists every field, without the rules of invalid tags.
+type structInfo struct {
+	fields []structField
+	err    error
+}
+
+var structCache sync.Map // reflect.Type -> structInfo
+
+// walker validates a value graph once per pointer and slice, so cyclic
+// values terminate.
+type walker struct {
+	ctx     context.Context
+	profile Profile
+	visited map[visit]bool
+}
+
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func newWalker(ctx context.Context, p Profile) *walker {
+	return &walker{ctx: ctx, profile: p, visited: map[visit]bool{}}
+}
+
+// seen marks val, a pointer or slice, and reports whether it was already
+// walked.
+func (w *walker) seen(val reflect.Value) bool {
+	v := visit{ptr: val.Pointer(), typ: val.Type()}
+	if w.visited[v] {
+		return true
+	}
+	w.visited[v] = true
+	return false
+}
+
+func (w *walker) structure(prefix string, val reflect.Value) errors.LayerError {
+	fields, err := structFields(val.Type())
+	if err != nil {
+		return errors.WithCause(errors.NewApplicationError(errors.ErrInternal, errors.InternalError, "Internal error"), err)
+	}
+	for _, f := range fields {
+		fv, ok := fieldByIndex(val, f.index)
+		if !ok || !fv.CanInterface() {
+			continue
+		}
+		name := f.name
+		if prefix != "" {
+			name = prefix + "." + f.name
+		}
+		if !w.profile.active(f.groups) {
+			continue
+		}
+		if w.profile.Partial && absent(fv) {
+			continue
+		}
+		value := indirect(fv.Interface())
+		for _, opt := range f.opts {
+			if err := opt(name, value); err != nil {
+				return err
+			}
+		}
+		if err := w.nested(name, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nested descends into struct values and the elements of slices and arrays,
+// then runs the Validate method of the value itself.
+func (w *walker) nested(name string, val reflect.Value) errors.LayerError {
+	if !val.IsValid() {
+		return nil
+	}
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() || (val.Kind() == reflect.Ptr && w.seen(val)) {
+			return nil
+		}
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Struct:
+		if err := w.structure(name, val); err != nil {
+			return err
+		}
+	case reflect.Slice:
+		if val.Len() > 0 && w.seen(val) {
+			return nil
+		}
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			if err := w.nested(fmt.Sprintf("%s[%d]", name, i), val.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return validateSelf(w.ctx, name, val)
+}
+
+// absent reports whether a field was left out of a partial payload.
+func absent(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return val.IsNil()
+	}
+	return false
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex without the panic on nil
+// embedded pointers.
+func fieldByIndex(val reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				return reflect.Value{}, false
+			}
+			val = val.Elem()
+		}
+		val = val.Field(x)
+	}
+	return val, true
+}
+
+func structFields(t reflect.Type) ([]structField, error) {
+	if cached, ok := structCache.Load(t); ok {
+		info := cached.(structInfo)
+		return info.fields, info.err
+	}
+	fields, err := collectFields(t, nil)
+	structCache.Store(t, structInfo{fields: fields, err: err})
+	return fields, err
+}
+
+// collectFields lists the exported fields of t, flattening untagged embedded
+// structs the way encoding/json does. It returns the first invalid tag as
+// the error, and lists that field without rules.
+func collectFields(t reflect.Type, index []int) ([]structField, error) {
+	var fields []structField
+	var firstErr error
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		idx := append(append([]int(nil), index...), i)
+		name, skip := jsonName(sf)
+		if skip {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			et := sf.Type
+			if et.Kind() == reflect.Ptr {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				embedded, err := collectFields(et, idx)
+				if firstErr == nil {
+					firstErr = err
+				}
+				fields = append(fields, embedded...)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		tag, groups := splitGroups(sf.Tag.Get("validate"))
+		opts, err := parseRules(tag)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("validation: %s.%s: %w", t, sf.Name, err)
+		}
+		fields = append(fields, structField{index: idx, name: name, opts: opts, groups: groups})
+	}
+	return fields, firstErr
+}
+
+func jsonName(sf reflect.StructField) (name string, skip bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ = strings.Cut(tag, ",")
+	return name, false
+}
+
+// splitGroups removes the groups rule from a validate tag and returns the
+// remaining rules and the groups.
+func splitGroups(tag string) (string, []string) {
+	if !strings.Contains(tag, "groups=") {
+		return tag, nil
+	}
+	var rules, groups []string
+	for _, rule := range strings.Split(tag, ",") {
+		if arg, ok := strings.CutPrefix(strings.TrimSpace(rule), "groups="); ok {
+			groups = append(groups, strings.Fields(arg)...)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return strings.Join(rules, ","), groups
+}
+
+// parseRules turns a validate tag into options. Rules after omitempty are
+// wrapped in Optional.
+func parseRules(tag string) ([]ValidationOption, error) {
+	if tag == "" {
+		return nil, nil
+	}
+	var opts []ValidationOption
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if name == "omitempty" {
+			rest, err := parseRules(strings.Join(rules[i+1:], ","))
+			if err != nil {
+				return nil, err
+			}
+			return append(opts, Optional(rest...)), nil
+		}
+		opt, err := tagRule(name, arg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	}
+	return opts, nil
+}
+
+var formatRules = map[string]func(msg ...string) ValidationOption{
+	"email":    Email,
+	"e164":     PhoneE164,
+	"country":  CountryISO3166,
+	"currency": CurrencyISO4217,
+	"ip":       IP,
+	"ipv4":     IPv4,
+	"ipv6":     IPv6,
+	"cidr":     CIDR,
+	"slug":     Slug,
+	"base64":   Base64,
+	"hex":      Hex,
+}
+
+func tagRule(name, arg string) (ValidationOption, error) {
+	switch name {
+	case "required":
+		return Required(), nil
+	case "uuid":
+		return UUID(), nil
+	case "url":
+		return URL(), nil
+	case "min", "max":
+		n, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q needs a number", name)
+		}
+		return bound(n, name == "min"), nil
+	case "oneof":
+		allowed := strings.Fields(arg)
+		if len(allowed) == 0 {
+			return nil, fmt.Errorf("rule %q needs at least one value", name)
+		}
+		return oneOf(allowed), nil
+	}
+	if rule, ok := formatRules[name]; ok {
+		return rule(), nil
+	}
+	return nil, fmt.Errorf("unknown rule %q", name)
+}
+
+// bound checks the length of strings, slices and maps, and the value of
+// numbers.
+func bound(limit float64, min bool) ValidationOption {
+	length := MaxLength(int(limit))
+	if min {
+		length = MinLength(int(limit))
+	}
+	return describe(func(field string, value any) errors.LayerError {
+		val := reflect.ValueOf(value)
+		var n float64
+		entries := false
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = float64(val.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n = float64(val.Uint())
+		case reflect.Float32, reflect.Float64:
+			n = val.Float()
+		case reflect.Map:
+			n = float64(val.Len())
+			entries = true
+		default:
+			return length(field, value)
+		}
+		if (min && n >= limit) || (!min && n <= limit) {
+			return nil
+		}
+		key, word := "max", "at most"
+		if min {
+			key, word = "min", "at least"
+		}
+		message := fmt.Sprintf("Field '%s' must be %s %v", field, word, limit)
+		if entries {
+			message = fmt.Sprintf("Field '%s' must have %s %v entries", field, word, limit)
+		}
+		return errors.NewValidationError(errors.ErrInvalidFormat, message, map[string]any{"field": field, key: limit})
+	}, func(p *schemaProbe) { p.bound(limit, min) })
+}
+
+func oneOf(allowed []string) ValidationOption {
+	return describe(func(field string, value any) errors.LayerError {
+		if value != nil {
+			s := fmt.Sprint(value)
+			for _, a := range allowed {
+				if s == a {
+					return nil
+				}
+			}
+		}
+		return errors.NewValidationError(errors.ErrInvalidFormat,
+			fmt.Sprintf("Field '%s' must be one of: %s", field, strings.Join(allowed, ", ")),
+			map[string]any{"field": field, "allowed": allowed})
+	}, func(p *schemaProbe) { p.enum(allowed) })
+}
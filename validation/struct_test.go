@@ -0,0 +1,126 @@
+package validation
+
+import (
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+type selectionDTO struct {
+	MarketID string  `json:"market_id" validate:"required,uuid"`
+	Odds     float64 `json:"odds" validate:"min=1.01"`
+}
+
+type Audit struct {
+	Channel string `json:"channel" validate:"oneof=web ios android"`
+}
+
+type betSlipDTO struct {
+	Audit
+	Email      string            `json:"email" validate:"required,email"`
+	Currency   string            `json:"currency" validate:"omitempty,currency"`
+	Stake      int64             `json:"stake" validate:"min=1,max=10000"`
+	Reference  *string           `json:"reference" validate:"omitempty,max=8"`
+	Selections []selectionDTO    `json:"selections" validate:"required,max=3"`
+	Tags       map[string]string `json:"tags" validate:"max=1"`
+	Ignored    string            `json:"-" validate:"required"`
+	internal   string
+}
+
+func validSlip() betSlipDTO {
+	return betSlipDTO{
+		Audit:      Audit{Channel: "web"},
+		Email:      "punter@example.com",
+		Stake:      500,
+		Selections: []selectionDTO{{MarketID: "123e4567-e89b-12d3-a456-426614174000", Odds: 2.5}},
+	}
+}
+
+func TestStruct(t *testing.T) {
+	long := "REF-0123456"
+
+	tests := []struct {
+		name      string
+		mutate    func(s *betSlipDTO)
+		wantCode  errors.ErrorCode
+		wantField string
+	}{
+		{"Valid", func(s *betSlipDTO) {}, "", ""},
+		{"Missing required", func(s *betSlipDTO) { s.Email = "" }, errors.ErrMissingRequired, "email"},
+		{"Invalid email", func(s *betSlipDTO) { s.Email = "punter" }, errors.ErrInvalidEmail, "email"},
+		{"Omitempty skips zero", func(s *betSlipDTO) { s.Currency = "" }, "", ""},
+		{"Omitempty checks value", func(s *betSlipDTO) { s.Currency = "XYZ" }, errors.ErrInvalidCurrency, "currency"},
+		{"Number below min", func(s *betSlipDTO) { s.Stake = 0 }, errors.ErrInvalidFormat, "stake"},
+		{"Number above max", func(s *betSlipDTO) { s.Stake = 10001 }, errors.ErrInvalidFormat, "stake"},
+		{"Pointer length", func(s *betSlipDTO) { s.Reference = &long }, errors.ErrInvalidFormat, "reference"},
+		{"Slice length", func(s *betSlipDTO) {
+			s.Selections = append(s.Selections, s.Selections[0], s.Selections[0], s.Selections[0])
+		}, errors.ErrInvalidFormat, "selections"},
+		{"Map length", func(s *betSlipDTO) { s.Tags = map[string]string{"a": "1", "b": "2"} }, errors.ErrInvalidFormat, "tags"},
+		{"Nested element", func(s *betSlipDTO) { s.Selections = append(s.Selections, selectionDTO{MarketID: "abc", Odds: 2}) }, errors.ErrInvalidUUID, "selections[1].market_id"},
+		{"Nested number", func(s *betSlipDTO) { s.Selections[0].Odds = 1 }, errors.ErrInvalidFormat, "selections[0].odds"},
+		{"Embedded struct", func(s *betSlipDTO) { s.Channel = "fax" }, errors.ErrInvalidFormat, "channel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slip := validSlip()
+			tt.mutate(&slip)
+			err := Struct(&slip)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("Struct() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Struct() expected %s, got nil", tt.wantCode)
+			}
+			if err.Code() != tt.wantCode || err.Details()["field"] != tt.wantField {
+				t.Errorf("Struct() = %s on %v, want %s on %s", err.Code(), err.Details()["field"], tt.wantCode, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestStruct_NonStructs(t *testing.T) {
+	var nilSlip *betSlipDTO
+	for _, v := range []any{nil, nilSlip, map[string]any{"a": 1}, 42} {
+		if err := Struct(v); err != nil {
+			t.Errorf("Struct(%v) unexpected error: %v", v, err)
+		}
+	}
+}
+
+type audit struct {
+	Channel string `json:"channel" validate:"required"`
+}
+
+func TestStruct_UnexportedEmbedded(t *testing.T) {
+	var dto struct {
+		*audit
+		Email string `json:"email" validate:"required"`
+	}
+	dto.audit, dto.Email = &audit{Channel: "web"}, "punter@example.com"
+	if err := Struct(&dto); err != nil {
+		t.Errorf("Struct() unexpected error: %v", err)
+	}
+	dto.Channel = ""
+	if err := Struct(&dto); err == nil || err.Details()["field"] != "channel" {
+		t.Errorf("Struct() error = %v, want required on channel", err)
+	}
+}
+
+func TestStruct_UnknownRule(t *testing.T) {
+	err := Struct(struct {
+		Name string `validate:"required,shiny"`
+	}{Name: "x"})
+	if err == nil || err.Code() != errors.ErrInternal || err.Type() != errors.InternalError {
+		t.Fatalf("Struct() = %v, want %s internal error", err, errors.ErrInternal)
+	}
+	if cause := stderrors.Unwrap(err); cause == nil || !strings.Contains(cause.Error(), `unknown rule "shiny"`) {
+		t.Errorf("Unwrap() = %v, want the unknown rule", cause)
+	}
+}
@@ -0,0 +1,226 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// AgeTable resolves the legal betting age of a jurisdiction, identified by an
+// ISO 3166 code such as "MX" or a subdivision such as "US-NJ".
+type AgeTable interface {
+	LegalAge(jurisdiction string) (int, bool)
+}
+
+type StaticAgeTable map[string]int
+
+func (t StaticAgeTable) LegalAge(jurisdiction string) (int, bool) {
+	age, ok := t[jurisdiction]
+	return age, ok
+}
+
+// DefaultAgeTable holds the legal betting age of the jurisdictions Betmates
+// operates in. Use Dates.Ages to supply other compliance data.
+var DefaultAgeTable = StaticAgeTable{
+	"MX":    18,
+	"CO":    18,
+	"AR":    18,
+	"BR":    18,
+	"ES":    18,
+	"GB":    18,
+	"CA-ON": 19,
+	"US-AZ": 21,
+	"US-CO": 21,
+	"US-IL": 21,
+	"US-MI": 21,
+	"US-NJ": 21,
+	"US-NV": 21,
+	"US-NY": 21,
+	"US-PA": 21,
+	"US-KY": 18,
+	"US-NH": 18,
+	"US-RI": 18,
+	"US-WY": 18,
+}
+
+// Dates builds the options that depend on the current time or on the legal
+// age of a jurisdiction. Zero fields take the defaults: clock.System() and
+// DefaultAgeTable. The package-level NotInFuture and MinAge use Dates{}.
+//
+//	dates := validation.Dates{Clock: clock.NewFake(start), Ages: complianceTable}
+//	err := validation.Validate(validation.Field("birth_date", req.BirthDate, dates.MinAge(18, "MX")))
+type Dates struct {
+	Clock clock.Clock
+	Ages  AgeTable
+}
+
+func (d Dates) now() time.Time {
+	if d.Clock == nil {
+		return clock.System().Now()
+	}
+	return d.Clock.Now()
+}
+
+func (d Dates) legalAge(jurisdiction string) (int, bool) {
+	if d.Ages == nil {
+		return DefaultAgeTable.LegalAge(jurisdiction)
+	}
+	return d.Ages.LegalAge(jurisdiction)
+}
+
+func Before(limit time.Time, msg ...string) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		message := fmt.Sprintf("Field '%s' must be before %s", field, limit.Format(time.RFC3339))
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		t, err := timeValue(field, value)
+		if err != nil {
+			return err
+		}
+		if !t.Before(limit) {
+			return errors.NewValidationError(errors.ErrInvalidDate, message, map[string]any{"field": field, "before": limit})
+		}
+		return nil
+	}
+}
+
+func After(limit time.Time, msg ...string) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		message := fmt.Sprintf("Field '%s' must be after %s", field, limit.Format(time.RFC3339))
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		t, err := timeValue(field, value)
+		if err != nil {
+			return err
+		}
+		if !t.After(limit) {
+			return errors.NewValidationError(errors.ErrInvalidDate, message, map[string]any{"field": field, "after": limit})
+		}
+		return nil
+	}
+}
+
+// Within validates that the value falls in the inclusive range [start, end].
+func Within(start, end time.Time, msg ...string) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		message := fmt.Sprintf("Field '%s' must be between %s and %s", field, start.Format(time.RFC3339), end.Format(time.RFC3339))
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		t, err := timeValue(field, value)
+		if err != nil {
+			return err
+		}
+		if t.Before(start) || t.After(end) {
+			return errors.NewValidationError(errors.ErrInvalidDate, message, map[string]any{"field": field, "start": start, "end": end})
+		}
+		return nil
+	}
+}
+
+func NotInFuture(msg ...string) ValidationOption {
+	return Dates{}.NotInFuture(msg...)
+}
+
+// NotInFuture validates that the value is not after the current time of d.
+func (d Dates) NotInFuture(msg ...string) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		message := fmt.Sprintf("Field '%s' must not be in the future", field)
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		t, err := timeValue(field, value)
+		if err != nil {
+			return err
+		}
+		if t.After(d.now()) {
+			return errors.NewValidationError(errors.ErrInvalidDate, message, map[string]any{"field": field})
+		}
+		return nil
+	}
+}
+
+// TimeFormat validates that a string value parses with the given layout.
+func TimeFormat(layout string, msg ...string) ValidationOption {
+	return describe(func(field string, value any) errors.LayerError {
+		message := fmt.Sprintf("Field '%s' must match the time layout %s", field, layout)
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		str, ok := value.(string)
+		if !ok {
+			return errors.NewValidationError(errors.ErrInvalidDate, message, map[string]any{"field": field, "layout": layout})
+		}
+		if _, err := time.Parse(layout, str); err != nil {
+			return errors.NewValidationError(errors.ErrInvalidDate, message, map[string]any{"field": field, "layout": layout, "value": str})
+		}
+		return nil
+	}, func(p *schemaProbe) {
+		switch layout {
+		case time.RFC3339:
+			p.schema.merge(&Schema{Format: "date-time"})
+		case time.DateOnly:
+			p.schema.merge(&Schema{Format: "date"})
+		}
+	})
+}
+
+// MinAge validates that a birth date is at least the legal age of the
+// jurisdiction, and never less than years. Unknown jurisdictions fall back
+// to years. People born on February 29 come of age on March 1 in non-leap
+// years.
+func MinAge(years int, jurisdiction string, msg ...string) ValidationOption {
+	return Dates{}.MinAge(years, jurisdiction, msg...)
+}
+
+// MinAge is MinAge with the clock and age table of d.
+func (d Dates) MinAge(years int, jurisdiction string, msg ...string) ValidationOption {
+	return func(field string, value any) errors.LayerError {
+		required := years
+		if age, ok := d.legalAge(jurisdiction); ok && age > required {
+			required = age
+		}
+		message := fmt.Sprintf("Field '%s' must be at least %d years ago", field, required)
+		if len(msg) > 0 {
+			message = msg[0]
+		}
+		birth, err := timeValue(field, value)
+		if err != nil {
+			return err
+		}
+		current := d.now().In(birth.Location())
+		if birth.AddDate(required, 0, 0).After(current) {
+			return errors.NewValidationError(errors.ErrUnderage, message, map[string]any{
+				"field":        field,
+				"min_age":      required,
+				"jurisdiction": jurisdiction,
+			})
+		}
+		return nil
+	}
+}
+
+// timeValue accepts time.Time values and strings in RFC 3339 or time.DateOnly.
+func timeValue(field string, value any) (time.Time, errors.LayerError) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+		if t, err := time.Parse(time.DateOnly, v); err == nil {
+			return t, nil
+		}
+		return time.Time{}, errors.NewValidationError(errors.ErrInvalidDate,
+			fmt.Sprintf("Field '%s' must be a valid date", field),
+			map[string]any{"field": field, "value": v})
+	}
+	return time.Time{}, errors.NewValidationError(errors.ErrInvalidDate,
+		fmt.Sprintf("Field '%s' must be a valid date", field),
+		map[string]any{"field": field})
+}
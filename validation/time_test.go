@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/clock"
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+func TestTimeValidators(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	mid := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	dates := Dates{Clock: clock.NewFake(mid)}
+
+	tests := []struct {
+		name    string
+		value   any
+		opt     ValidationOption
+		wantErr bool
+	}{
+		{"Before passes", start, Before(end), false},
+		{"Before fails", end, Before(start), true},
+		{"After passes", "2024-06-15", After(start), false},
+		{"After fails on equal", start, After(start), true},
+		{"Within passes", mid, Within(start, end), false},
+		{"Within includes bounds", end, Within(start, end), false},
+		{"Within fails", "2025-01-01T00:00:00Z", Within(start, end), true},
+		{"NotInFuture passes", start, dates.NotInFuture(), false},
+		{"NotInFuture fails", end, dates.NotInFuture(), true},
+		{"TimeFormat passes", "15/06/2024", TimeFormat("02/01/2006"), false},
+		{"TimeFormat fails", "2024-06-15", TimeFormat("02/01/2006"), true},
+		{"Unparseable date", "yesterday", Before(end), true},
+		{"Non date value", 42, Before(end), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(Field("date", tt.value, tt.opt))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && err.Code() != errors.ErrInvalidDate {
+				t.Errorf("Code() = %v, want %v", err.Code(), errors.ErrInvalidDate)
+			}
+		})
+	}
+}
+
+func TestMinAge(t *testing.T) {
+	today := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	dates := Dates{Clock: clock.NewFake(today)}
+
+	tests := []struct {
+		name         string
+		birth        string
+		years        int
+		jurisdiction string
+		wantErr      bool
+	}{
+		{"18 today in Mexico", "2006-06-15", 18, "MX", false},
+		{"18 tomorrow in Mexico", "2006-06-16", 18, "MX", true},
+		{"19 in New Jersey", "2005-01-01", 18, "US-NJ", true},
+		{"21 in New Jersey", "2003-06-15", 18, "US-NJ", false},
+		{"Unknown jurisdiction falls back to years", "2006-01-01", 18, "ZZ", false},
+		{"Years raise the jurisdiction age", "2006-01-01", 21, "MX", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(Field("birth_date", tt.birth, dates.MinAge(tt.years, tt.jurisdiction)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && err.Code() != errors.ErrUnderage {
+				t.Errorf("Code() = %v, want %v", err.Code(), errors.ErrUnderage)
+			}
+		})
+	}
+}
+
+func TestMinAge_LeapDayBirthday(t *testing.T) {
+	leap := time.Date(2004, 2, 29, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(time.Date(2022, 2, 28, 12, 0, 0, 0, time.UTC))
+	minAge := Dates{Clock: fake}.MinAge(18, "MX")
+
+	if err := Validate(Field("birth_date", leap, minAge)); err == nil {
+		t.Error("Expected leap-day birthday to be underage on February 28")
+	}
+	fake.Set(time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC))
+	if err := Validate(Field("birth_date", leap, minAge)); err != nil {
+		t.Errorf("Did not expect error on March 1: %v", err)
+	}
+}
+
+func TestDates_AgeTable(t *testing.T) {
+	t.Parallel()
+	dates := Dates{
+		Clock: clock.NewFake(time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)),
+		Ages:  StaticAgeTable{"MX": 25},
+	}
+
+	err := Validate(Field("birth_date", "2004-01-01", dates.MinAge(18, "MX")))
+	if err == nil {
+		t.Fatal("Expected custom jurisdiction table to be used")
+	}
+	if err.Details()["min_age"] != 25 {
+		t.Errorf("Details[min_age] = %v, want 25", err.Details()["min_age"])
+	}
+	// Other callers keep the default table.
+	if err := Validate(Field("birth_date", "2004-01-01", MinAge(18, "MX"))); err != nil {
+		t.Errorf("MinAge() with the default table unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+// Validatable is implemented by types that own their rules. Validate calls it
+// on field values and their slice elements, and Struct on nested values, and
+// both prefix the reported field with the value's path, so a "stake" failure
+// inside "bet" is reported as "bet.stake".
+type Validatable interface {
+	Validate() errors.LayerError
+}
+
+// ValidatableWithContext is the context-aware variant of Validatable, for
+// rules that look things up. It takes precedence when a type implements
+// both.
+type ValidatableWithContext interface {
+	ValidateWithContext(ctx context.Context) errors.LayerError
+}
+
+// ValidateContext is Validate with a context for ValidatableWithContext
+// values.
+func ValidateContext(ctx context.Context, fields ...ValidationField) errors.LayerError {
+	return Profile{}.ValidateContext(ctx, fields...)
+}
+
+// validateElements runs the Validate method of val, or of each element when
+// val is a slice or array. It does not look further, so values of foreign
+// types are never walked.
+func validateElements(ctx context.Context, name string, val reflect.Value) errors.LayerError {
+	val, ok := deref(val)
+	if !ok {
+		return nil
+	}
+	if val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
+		for i := 0; i < val.Len(); i++ {
+			elem, ok := deref(val.Index(i))
+			if !ok {
+				continue
+			}
+			if err := validateSelf(ctx, fmt.Sprintf("%s[%d]", name, i), elem); err != nil {
+				return err
+			}
+		}
+	}
+	return validateSelf(ctx, name, val)
+}
+
+// deref follows pointers and interfaces, reporting false for nil.
+func deref(val reflect.Value) (reflect.Value, bool) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return reflect.Value{}, false
+		}
+		val = val.Elem()
+	}
+	return val, val.IsValid()
+}
+
+// validateSelf runs the Validate method of val, if any, preferring the
+// pointer method set when val is addressable.
+func validateSelf(ctx context.Context, name string, val reflect.Value) errors.LayerError {
+	if !val.CanInterface() {
+		return nil
+	}
+	value := val.Interface()
+	if val.CanAddr() {
+		value = val.Addr().Interface()
+	}
+	var err errors.LayerError
+	switch v := value.(type) {
+	case ValidatableWithContext:
+		err = v.ValidateWithContext(ctx)
+	case Validatable:
+		err = v.Validate()
+	default:
+		return nil
+	}
+	return prefixError(name, err)
+}
+
+// prefixError reports err under path: its "field" detail, the fields of its
+// causes and the quoted field in its message gain the prefix. An error
+// without a field is reported on path itself.
+func prefixError(path string, err errors.LayerError) errors.LayerError {
+	if err == nil || path == "" {
+		return err
+	}
+	details := prefixDetails(path, err.Details())
+	message := err.Error()
+	if field, ok := err.Details()["field"].(string); ok && field != "" {
+		message = strings.Replace(message, "'"+field+"'", "'"+details["field"].(string)+"'", 1)
+	}
+	return errors.WithCause(rebuild(err, message, details), err)
+}
+
+func prefixDetails(path string, details map[string]any) map[string]any {
+	out := make(map[string]any, len(details)+1)
+	for k, v := range details {
+		out[k] = v
+	}
+	field, _ := details["field"].(string)
+	out["field"] = joinPath(path, field)
+	if causes, ok := details["causes"].([]map[string]any); ok {
+		prefixed := make([]map[string]any, len(causes))
+		for i, c := range causes {
+			cause := make(map[string]any, len(c))
+			for k, v := range c {
+				cause[k] = v
+			}
+			if d, ok := c["details"].(map[string]any); ok {
+				cause["details"] = prefixDetails(path, d)
+			}
+			prefixed[i] = cause
+		}
+		out["causes"] = prefixed
+	}
+	return out
+}
+
+func joinPath(prefix, field string) string {
+	switch {
+	case field == "":
+		return prefix
+	case strings.HasPrefix(field, "["):
+		return prefix + field
+	}
+	return prefix + "." + field
+}
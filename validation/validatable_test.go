@@ -0,0 +1,180 @@
+package validation
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/Joel-Medina-Osornio/betmates_backend_core/errors"
+)
+
+type stake struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency" validate:"required"`
+}
+
+func (s stake) Validate() errors.LayerError {
+	return Validate(Field("amount", s.Amount, Custom(func(v any) bool { return v.(int64) > 0 }, "Field 'amount' must be positive")))
+}
+
+type leg struct {
+	Market string `json:"market"`
+}
+
+func (l *leg) Validate() errors.LayerError {
+	if l.Market == "" {
+		return Validate(
+			Field("market", l.Market, Required()),
+		)
+	}
+	if l.Market == "closed" {
+		return errors.NewBusinessRuleError(errors.ErrInvalidState, "Market is closed")
+	}
+	return nil
+}
+
+type marketKey struct{}
+
+type jurisdiction string
+
+func (j jurisdiction) ValidateWithContext(ctx context.Context) errors.LayerError {
+	if blocked, _ := ctx.Value(marketKey{}).(string); blocked == string(j) {
+		return errors.NewBusinessRuleError(errors.ErrInvalidBusinessRule, "Jurisdiction is blocked")
+	}
+	return nil
+}
+
+func (j jurisdiction) Validate() errors.LayerError {
+	panic("ValidateWithContext must take precedence")
+}
+
+type slip struct {
+	Stake        stake        `json:"stake"`
+	Legs         []leg        `json:"legs"`
+	Jurisdiction jurisdiction `json:"jurisdiction"`
+}
+
+func TestValidatable_Field(t *testing.T) {
+	tests := []struct {
+		name      string
+		field     ValidationField
+		wantCode  errors.ErrorCode
+		wantField any
+	}{
+		{"Valid value", Field("stake", stake{Amount: 10, Currency: "EUR"}), "", nil},
+		{"Method rule", Field("stake", stake{Amount: -1, Currency: "EUR"}), errors.ErrInvalidFormat, "stake.amount"},
+		{"Tag rule of nested struct", Field("stake", stake{Amount: 10}).Nested(), errors.ErrMissingRequired, "stake.currency"},
+		{"Tag rules skipped without Nested", Field("stake", stake{Amount: 10}), "", nil},
+		{"Pointer receiver", Field("leg", &leg{}), errors.ErrMissingRequired, "leg.market"},
+		{"Slice elements", Field("legs", []leg{{Market: "m1"}, {}}), errors.ErrMissingRequired, "legs[1].market"},
+		{"Error without field", Field("leg", &leg{Market: "closed"}), errors.ErrInvalidState, "leg"},
+		{"Nil pointer skipped", Field("leg", (*leg)(nil)), "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.field)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() expected %s, got nil", tt.wantCode)
+			}
+			if err.Code() != tt.wantCode || err.Details()["field"] != tt.wantField {
+				t.Errorf("Validate() = %s on %v, want %s on %v", err.Code(), err.Details()["field"], tt.wantCode, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidatable_PrefixKeepsError(t *testing.T) {
+	err := Validate(Field("bet", slip{Stake: stake{Amount: 0, Currency: "EUR"}}).Nested())
+	if err == nil {
+		t.Fatal("Validate() expected error, got nil")
+	}
+	if err.Details()["field"] != "bet.stake.amount" {
+		t.Errorf("Details[field] = %v, want bet.stake.amount", err.Details()["field"])
+	}
+	if err.Error() != "Field 'bet.stake.amount' must be positive" {
+		t.Errorf("Error() = %q, want the quoted field prefixed", err.Error())
+	}
+	if err.Layer() != errors.ApplicationLayer || err.Type() != errors.ValidationError {
+		t.Errorf("Layer/Type = %s/%s, want application/validation", err.Layer(), err.Type())
+	}
+
+	err = Validate(Field("bet", slip{Stake: stake{Amount: 1, Currency: "EUR"}, Legs: []leg{{Market: "closed"}}}).Nested())
+	if err == nil || err.Layer() != errors.DomainLayer || err.Details()["field"] != "bet.legs[0]" {
+		t.Fatalf("Validate() = %v, want domain error on bet.legs[0]", err)
+	}
+	var cause errors.LayerError
+	if !stderrors.As(stderrors.Unwrap(err), &cause) || cause.Details() != nil {
+		t.Errorf("Unwrap() = %v, want the unprefixed original", stderrors.Unwrap(err))
+	}
+}
+
+func TestValidatable_Context(t *testing.T) {
+	ctx := context.WithValue(context.Background(), marketKey{}, "us-ut")
+	value := slip{Stake: stake{Amount: 1, Currency: "EUR"}, Jurisdiction: "us-ut"}
+
+	err := StructContext(ctx, value)
+	if err == nil || err.Code() != errors.ErrInvalidBusinessRule || err.Details()["field"] != "jurisdiction" {
+		t.Errorf("StructContext() = %v, want blocked jurisdiction", err)
+	}
+	if err := ValidateContext(ctx, Field("slip", value).Nested()); err == nil || err.Details()["field"] != "slip.jurisdiction" {
+		t.Errorf("ValidateContext() = %v, want slip.jurisdiction", err)
+	}
+	if err := StructContext(context.Background(), value); err != nil {
+		t.Errorf("StructContext() unexpected error: %v", err)
+	}
+}
+
+func TestValidatable_CausesArePrefixed(t *testing.T) {
+	err := Validate(Field("bet", merged{}))
+	causes, ok := err.Details()["causes"].([]map[string]any)
+	if !ok || len(causes) != 2 {
+		t.Fatalf("Details[causes] = %v", err.Details()["causes"])
+	}
+	if got := causes[1]["details"].(map[string]any)["field"]; got != "bet.emails[1]" {
+		t.Errorf("cause field = %v, want bet.emails[1]", got)
+	}
+}
+
+type node struct {
+	Name string `json:"name" validate:"required"`
+	Next *node  `json:"next"`
+}
+
+type foreign struct {
+	Count int `json:"count" validate:"gte=0"`
+}
+
+func TestValidatable_Walk(t *testing.T) {
+	cycle := &node{Name: "a"}
+	cycle.Next = &node{Name: "b", Next: cycle}
+	if err := Validate(Field("list", cycle).Nested()); err != nil {
+		t.Errorf("Validate() on a cycle unexpected error: %v", err)
+	}
+	if err := Struct(cycle); err != nil {
+		t.Errorf("Struct() on a cycle unexpected error: %v", err)
+	}
+	cycle.Next.Name = ""
+	if err := Validate(Field("list", cycle).Nested()); err == nil || err.Details()["field"] != "list.next.name" {
+		t.Errorf("Validate() = %v, want list.next.name", err)
+	}
+
+	if err := Validate(Field("other", foreign{Count: 1}), Field("others", []foreign{{}})); err != nil {
+		t.Errorf("Validate() walked a foreign type: %v", err)
+	}
+	if err := Validate(Field("other", foreign{}).Nested()); err == nil || err.Code() != errors.ErrInternal {
+		t.Errorf("Validate() = %v, want %s for a foreign tag", err, errors.ErrInternal)
+	}
+}
+
+type merged struct{}
+
+func (merged) Validate() errors.LayerError {
+	return Validate(Field("emails", []string{"bad", "worse"}, Each(Email())))
+}
@@ -12,28 +12,27 @@ import (
 type ValidationOption func(field string, value any) errors.LayerError
 
 func Field(field string, value any, opts ...ValidationOption) ValidationField {
-	return ValidationField{field, value, opts}
+	return ValidationField{Field: field, Value: value, Options: opts}
 }
 
 type ValidationField struct {
-	Field   string
-	Value   any
-	Options []ValidationOption
+	Field      string
+	Value      any
+	Options    []ValidationOption
+	Transforms []Transformer
+	Groups     []string
+	// Walk also validates the value as Struct does; see Nested.
+	Walk bool
+	// Indirect passes what pointers point to to the options; see Deref.
+	Indirect bool
 }
 
 func Validate(fields ...ValidationField) errors.LayerError {
-	for _, f := range fields {
-		for _, opt := range f.Options {
-			if err := opt(f.Field, f.Value); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
+	return Profile{}.Validate(fields...)
 }
 
 func Required(msg ...string) ValidationOption {
-	return func(field string, value any) errors.LayerError {
+	return describe(func(field string, value any) errors.LayerError {
 		message := fmt.Sprintf("Field '%s' is required", field)
 		if len(msg) > 0 {
 			message = msg[0]
@@ -53,11 +52,11 @@ func Required(msg ...string) ValidationOption {
 			}
 		}
 		return nil
-	}
+	}, func(p *schemaProbe) { p.required = true })
 }
 
 func Email(msg ...string) ValidationOption {
-	return func(field string, value any) errors.LayerError {
+	return describe(func(field string, value any) errors.LayerError {
 		message := fmt.Sprintf("Field '%s' must be a valid email", field)
 		if len(msg) > 0 {
 			message = msg[0]
@@ -71,11 +70,11 @@ func Email(msg ...string) ValidationOption {
 			return errors.NewValidationError(errors.ErrInvalidEmail, message, map[string]any{"field": field, "value": email})
 		}
 		return nil
-	}
+	}, func(p *schemaProbe) { p.schema.merge(&Schema{Format: "email"}) })
 }
 
 func MinLength(min int, msg ...string) ValidationOption {
-	return func(field string, value any) errors.LayerError {
+	return describe(func(field string, value any) errors.LayerError {
 		message := fmt.Sprintf("Field '%s' must have at least %d characters", field, min)
 		if len(msg) > 0 {
 			message = msg[0]
@@ -92,11 +91,11 @@ func MinLength(min int, msg ...string) ValidationOption {
 			}
 		}
 		return nil
-	}
+	}, func(p *schemaProbe) { p.length(min, true) })
 }
 
 func MaxLength(max int, msg ...string) ValidationOption {
-	return func(field string, value any) errors.LayerError {
+	return describe(func(field string, value any) errors.LayerError {
 		message := fmt.Sprintf("Field '%s' must have at most %d characters", field, max)
 		if len(msg) > 0 {
 			message = msg[0]
@@ -113,11 +112,11 @@ func MaxLength(max int, msg ...string) ValidationOption {
 			}
 		}
 		return nil
-	}
+	}, func(p *schemaProbe) { p.length(max, false) })
 }
 
 func Pattern(pattern string, msg ...string) ValidationOption {
-	return func(field string, value any) errors.LayerError {
+	return describe(func(field string, value any) errors.LayerError {
 		message := fmt.Sprintf("Field '%s' must match pattern", field)
 		if len(msg) > 0 {
 			message = msg[0]
@@ -131,7 +130,7 @@ func Pattern(pattern string, msg ...string) ValidationOption {
 			return errors.NewValidationError(errors.ErrInvalidFormat, message, map[string]any{"field": field, "pattern": pattern})
 		}
 		return nil
-	}
+	}, func(p *schemaProbe) { p.schema.merge(&Schema{Pattern: pattern}) })
 }
 
 func Custom(validator func(value any) bool, msg string) ValidationOption {